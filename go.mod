@@ -3,3 +3,8 @@ module ryan/chess
 go 1.20
 
 require github.com/TwiN/go-color v1.4.0 // direct
+
+require (
+	github.com/chzyer/readline v1.5.1
+	golang.org/x/sys v0.0.0-20220310020820-b874c991c1a5 // indirect
+)
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// Line editor for every prompt - it keeps the command history for the session
+var (
+	console     *readline.Instance
+	inputClosed bool
+)
+
+func open_console() error {
+	var err error
+	console, err = readline.NewEx(&readline.Config{
+		HistoryLimit:      500,
+		HistorySearchFold: true,
+	})
+	return err
+}
+
+// get_command reads one line. It reports false when Ctrl-C is pressed, which
+// callers treat as stepping back, and for every read once the input is closed
+// with Ctrl-D, which steps all the way back to resigning the game.
+func get_command(prompt string) (string, bool) {
+	if inputClosed {
+		return "", false
+	}
+	console.SetPrompt(strings.TrimLeft(prompt, "\n") + ": ")
+	if strings.HasPrefix(prompt, "\n") {
+		println()
+	}
+	line, err := console.Readline()
+	if err == io.EOF {
+		inputClosed = true
+	}
+	if err != nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(line), true
+}
+
+// get_input reads a number, asking again until one is typed.
+func get_input(prompt string) (int, bool) {
+	for {
+		command, ok := get_command(prompt)
+		if !ok {
+			return 0, false
+		}
+		choice, err := strconv.Atoi(command)
+		if err == nil {
+			return choice, true
+		}
+		println("ERROR: Please type a number.")
+	}
+}
+
+// get_confirmation asks a yes or no question. Closed input gives the answer
+// for an empty line.
+func get_confirmation(prompt string, empty bool) bool {
+	for {
+		command, ok := get_command(prompt)
+		if !ok || command == "" {
+			return empty
+		}
+		switch strings.ToLower(command) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		println("ERROR: Please answer yes or no.")
+	}
+}
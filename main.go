@@ -1,151 +1,58 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/TwiN/go-color"
+	"ryan/chess/pkg/chess"
+	"ryan/chess/pkg/engine"
 )
 
-type pieceType int
-type playerColor int
-type boardColor bool
+var pgnPath = flag.String("pgn", "game.pgn", "file the game is saved to as PGN")
 
-/* Enums */
+// Whether the board is shown from Black's side
+var flipped = false
 
+// Menu entries that aren't pieces
 const (
-	Empty pieceType = iota
-	Pawn
-	Rook
-	Knight
-	Bishop
-	Queen
-	King
+	resignChoice    = -1
+	claimDrawChoice = -2
+	typedMoveChoice = -3
+	quitChoice      = -4
 )
 
-const (
-	Blank playerColor = iota
-	White
-	Black
-)
-
-const (
-	Board_White boardColor = true
-	Board_Black boardColor = false
-)
-
-func (p pieceType) String() string {
-	switch p {
-	case Pawn:
+/* Functions */
+func piece_symbol(piece chess.Piece) string {
+	switch piece.Type {
+	case chess.Pawn:
 		return "p"
-	case Rook:
+	case chess.Rook:
 		return "r"
-	case Knight:
+	case chess.Knight:
 		return "k"
-	case Bishop:
+	case chess.Bishop:
 		return "b"
-	case Queen:
+	case chess.Queen:
 		return "Q"
-	case King:
+	case chess.King:
 		return "K"
-	case Empty:
+	case chess.NoPieceType:
 		return "."
 	}
 	return "??? :)"
 }
 
-func (c playerColor) String() string {
-	switch c {
-	case White:
-		return "White"
-	case Black:
-		return "Black"
-	case Blank:
-		return ""
-	}
-	return "??? :)"
-}
-
-func (bc boardColor) String() string {
-	switch bc {
-	case Board_White:
-		return "White"
-	case Board_Black:
-		return "Black"
-	}
-	return "??? :)"
-}
-
-/* Structs */
-type Piece struct {
-	pieceType pieceType
-	player    playerColor
-	rank      int
-	file      int
-	firstMove bool
-}
-
-func define_piece(t pieceType, p playerColor, r int, f int) Piece {
-	var piece Piece
-	piece.pieceType = t
-	piece.player = p
-	piece.rank = r
-	piece.file = f
-	piece.firstMove = true
-
-	return piece
-}
-
-/* Functions */
-func initialize_board() [8][8]Piece {
-	board := [8][8]Piece{}
-	for r := 0; r < len(board); r++ {
-		for f := 0; f < len(board[r]); f++ {
-			board[r][f] = Piece{}
-		}
-	}
-
-	return board
-}
-
-func fill_board(board [8][8]Piece) [8][8]Piece {
-	// White Player
-	board[0][0] = define_piece(Rook, White, 0, 0)
-	board[0][1] = define_piece(Knight, White, 0, 1)
-	board[0][2] = define_piece(Bishop, White, 0, 2)
-	board[0][3] = define_piece(King, White, 0, 3)
-	board[0][4] = define_piece(Queen, White, 0, 4)
-	board[0][5] = define_piece(Bishop, White, 0, 5)
-	board[0][6] = define_piece(Knight, White, 0, 6)
-	board[0][7] = define_piece(Rook, White, 0, 7)
-
-	for p := 0; p < len(board[1]); p++ {
-		board[1][p] = define_piece(Pawn, White, 1, p)
-	}
-
-	// Black Player
-	board[7][0] = define_piece(Rook, Black, 7, 0)
-	board[7][1] = define_piece(Knight, Black, 7, 1)
-	board[7][2] = define_piece(Bishop, Black, 7, 2)
-	board[7][3] = define_piece(King, Black, 7, 3)
-	board[7][4] = define_piece(Queen, Black, 7, 4)
-	board[7][5] = define_piece(Bishop, Black, 7, 5)
-	board[7][6] = define_piece(Knight, Black, 7, 6)
-	board[7][7] = define_piece(Rook, Black, 7, 7)
-
-	for p := 0; p < len(board[6]); p++ {
-		board[6][p] = define_piece(Pawn, Black, 6, p)
-	}
-
-	return board
-}
-
-func is_move(space [2]int, moves [][3]int) (bool, int) {
+func is_move(space chess.Square, moves []chess.Move) (bool, int) {
 	isHighlight := false
 	moveIndex := 0
 	for s := 0; s < len(moves); s++ {
-		if moves[s][0] == space[0] && moves[s][1] == space[1] {
+		if moves[s].To == space {
 			isHighlight = true
 			moveIndex = s
 		}
@@ -154,15 +61,31 @@ func is_move(space [2]int, moves [][3]int) (bool, int) {
 	return isHighlight, moveIndex
 }
 
-func print_board(board [8][8]Piece, moves [][3]int, currentPiece Piece) {
-	println("    A B C D E F G H")
+func print_board(position *chess.Position, moves []chess.Move, currentSpace chess.Square) {
+	currentPiece := position.PieceAt(currentSpace)
+	if flipped {
+		println("    H G F E D C B A")
+	} else {
+		println("    A B C D E F G H")
+	}
 	println("   ----------------")
-	for r := len(board) - 1; r >= 0; r-- {
-		print(r, " | ")
-		for f := len(board[r]) - 1; f >= 0; f-- {
-			isMove, moveIndex := is_move([2]int{r, f}, moves)
+	for row := 0; row < 8; row++ {
+		// White's side is at the bottom unless the board is flipped
+		r := 7 - row
+		if flipped {
+			r = row
+		}
+		print(r+1, " | ")
+		for column := 0; column < 8; column++ {
+			f := column
+			if flipped {
+				f = 7 - column
+			}
+			space := chess.NewSquare(f, r)
+			piece := position.PieceAt(space)
+			isMove, moveIndex := is_move(space, moves)
 			if isMove {
-				if currentPiece.player != board[r][f].player && board[r][f].player != Blank {
+				if currentPiece.Color != piece.Color && piece.Color != chess.NoColor {
 					// Attack Moves
 					if moveIndex > 9 {
 						print(color.InWhiteOverRed(moveIndex))
@@ -177,19 +100,19 @@ func print_board(board [8][8]Piece, moves [][3]int, currentPiece Piece) {
 						print(color.InCyanOverBlue(moveIndex) + " ")
 					}
 				}
-			} else if r == currentPiece.rank && f == currentPiece.file && currentPiece.pieceType != Empty {
+			} else if space == currentSpace && !currentPiece.IsEmpty() {
 				// Current Piece Highlight
-				if currentPiece.player == White {
-					print(color.InWhiteOverGreen(board[r][f].pieceType) + " ")
+				if currentPiece.Color == chess.White {
+					print(color.InWhiteOverGreen(piece_symbol(piece)) + " ")
 				} else {
-					print(color.InCyanOverGreen(board[r][f].pieceType) + " ")
+					print(color.InCyanOverGreen(piece_symbol(piece)) + " ")
 				}
 			} else {
 				// Other Pieces
-				if board[r][f].player == White {
-					print(color.InWhite(board[r][f].pieceType) + " ")
+				if piece.Color == chess.White {
+					print(color.InWhite(piece_symbol(piece)) + " ")
 				} else {
-					print(color.InCyan(board[r][f].pieceType) + " ")
+					print(color.InCyan(piece_symbol(piece)) + " ")
 				}
 			}
 		}
@@ -197,82 +120,44 @@ func print_board(board [8][8]Piece, moves [][3]int, currentPiece Piece) {
 	}
 }
 
-func get_input(prompt string) int {
-	println(prompt, ":")
-	var choice int
-	_, err := fmt.Scanf("%d", &choice)
-	if err != nil {
-		log.Fatal(err)
+// parse_move reads a move typed as e2e4, e2 e4, Nf3 or O-O. A promotion typed
+// without its piece, such as e7e8, asks the player which piece they want.
+func parse_move(position *chess.Position, command string) (chess.Move, bool) {
+	command = strings.ReplaceAll(command, " ", "")
+	if move, err := position.ParseSAN(command); err == nil {
+		return move, true
 	}
-
-	return choice
+	if move, err := position.ParseSAN(command + "q"); err == nil {
+		move.Promotion = prompt_promotion()
+		return move, move.IsPromotion()
+	}
+	return chess.Move{}, false
 }
 
-func get_space_format(space [2]int) string {
-	var pos string
-	switch space[1] {
-	case 0:
-		pos = "h"
-	case 1:
-		pos = "g"
-	case 2:
-		pos = "f"
-	case 3:
-		pos = "e"
-	case 4:
-		pos = "d"
-	case 5:
-		pos = "c"
-	case 6:
-		pos = "b"
-	case 7:
-		pos = "a"
-	default:
-		pos = "lmao"
-	}
-	pos += strconv.Itoa(space[0])
-	return pos
-}
+func save_game(game *chess.Game, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		println("ERROR: Could not save the game:", err.Error())
+		return
+	}
+	defer file.Close()
 
-func get_valid_pieces(board [8][8]Piece, player playerColor, lastMove [3]int, isInCheck bool, blockMoves [][2]int, enemyMoves [][3]int) []Piece {
-	validPieces := make([]Piece, 0)
-	if isInCheck {
-		for r := len(board) - 1; r >= 0; r-- {
-			for f := len(board[r]) - 1; f >= 0; f-- {
-				if board[r][f].player == player {
-					if board[r][f].pieceType == King {
-						// King - Check if king can escape the check
-						kingMoves := get_moves(board[r][f], board, lastMove, isInCheck)
-						kingMoves = get_valid_moves(board, kingMoves, board[r][f], isInCheck, enemyMoves, blockMoves, lastMove)
-						if len(kingMoves) > 0 {
-							validPieces = append(validPieces, board[r][f])
-						}
-					} else {
-						// Not King - Check if it can block the checking piece
-						validPiece := false
-						currMoves := get_moves(board[r][f], board, lastMove, isInCheck)
-						currMoves = get_valid_moves(board, currMoves, board[r][f], isInCheck, enemyMoves, blockMoves, lastMove)
-						for m := 0; m < len(currMoves); m++ {
-							for b := 0; b < len(blockMoves); b++ {
-								if currMoves[m][0] == blockMoves[b][0] && currMoves[m][1] == blockMoves[b][1] {
-									validPiece = true
-								}
-							}
-						}
-						if validPiece {
-							validPieces = append(validPieces, board[r][f])
-						}
-					}
-				}
-			}
-		}
+	if err := game.WritePGN(file); err != nil {
+		println("ERROR: Could not save the game:", err.Error())
+		return
+	}
+	println("Game saved to", path)
+}
 
-	} else {
-		for r := len(board) - 1; r >= 0; r-- {
-			for f := len(board[r]) - 1; f >= 0; f-- {
-				if board[r][f].player == player && len(get_moves(board[r][f], board, lastMove, isInCheck)) != 0 {
-					validPieces = append(validPieces, board[r][f])
-				}
+func get_valid_pieces(game *chess.Game) []chess.Square {
+	// Only offer pieces that have at least one legal move
+	validPieces := make([]chess.Square, 0)
+	position := game.Position()
+	for r := 7; r >= 0; r-- {
+		for f := 0; f < 8; f++ {
+			space := chess.NewSquare(f, r)
+			if len(position.LegalMovesFrom(space)) > 0 {
+				validPieces = append(validPieces, space)
 			}
 		}
 	}
@@ -280,712 +165,417 @@ func get_valid_pieces(board [8][8]Piece, player playerColor, lastMove [3]int, is
 	return validPieces
 }
 
-func select_piece(player playerColor, redo bool, pieces []Piece) (Piece, bool) {
+func select_piece(game *chess.Game, redo bool, pieces []chess.Square, claimableDraw chess.DrawRule) (chess.Square, chess.Move, bool, int) {
+	position := game.Position()
 	if !redo {
 		println("\n  === Available Pieces === ")
 		for p := 0; p < len(pieces); p++ {
-			fmt.Printf("%v: \t%v @ %v \t\t", p, pieces[p].pieceType.String(), get_space_format([2]int{pieces[p].rank, pieces[p].file}))
+			fmt.Printf("%v: \t%v @ %v \t\t", p, piece_symbol(position.PieceAt(pieces[p])), pieces[p])
 			if p%2 == 1 {
 				println()
 			}
 		}
 		println()
+		println("-1: \tResign")
+		if claimableDraw != chess.NoDrawRule {
+			println("-2: \tClaim draw by", claimableDraw.String())
+		}
+		println("flip: \tTurn the board around")
+		println("fen: \tPrint the position as FEN")
+		println("eval: \tShow how the engine scores the position")
+		println("save: \tSave the game as PGN to", *pgnPath)
+		println("Or type a square such as e2 to see its moves, or a move such as e2e4, Nf3 or O-O")
 	} else {
 		println("Invalid piece. Please select another one.\n")
 	}
 
-	choice := get_input("Select a piece to move")
-
-	if choice >= 0 && choice < len(pieces) {
-		return pieces[choice], true
-	}
-	return Piece{}, false
-}
+	for {
+		command, ok := get_command("Select a piece to move")
+		if !ok && inputClosed {
+			return chess.NoSquare, chess.Move{}, true, quitChoice
+		}
+		if !ok {
+			continue
+		}
+		switch command {
+		case "flip":
+			flipped = !flipped
+			print_board(position, make([]chess.Move, 0), chess.NoSquare)
+			continue
+		case "fen":
+			println(position.FEN())
+			continue
+		case "eval":
+			evaluation := engine.Evaluate(position)
+			fmt.Print(evaluation.String())
+			continue
+		case "save":
+			save_game(game, *pgnPath)
+			continue
+		}
 
-func get_valid_moves(board [8][8]Piece, moves [][3]int, piece Piece, isInCheck bool, enemyMoves [][3]int, blockMoves [][2]int, lastMove [3]int) [][3]int {
-	validMoves := make([][3]int, 0)
-	for m := 0; m < len(moves); m++ {
-		if (moves[m][0] >= 0 && moves[m][0] < 8) && (moves[m][1] >= 0 && moves[m][1] < 8) {
-			if board[moves[m][0]][moves[m][1]].player != piece.player {
-				if piece.pieceType != King {
-					if isInCheck {
-						for b := 0; b < len(blockMoves); b++ {
-							if moves[m][0] == blockMoves[b][0] && moves[m][1] == blockMoves[b][1] {
-								validMoves = append(validMoves, moves[m])
-							}
-						}
-					} else {
-						validMoves = append(validMoves, moves[m])
-					}
-				} else {
-					validMoves = append(validMoves, moves[m])
-				}
+		// Menu index
+		if choice, err := strconv.Atoi(command); err == nil {
+			if choice == resignChoice || (choice == claimDrawChoice && claimableDraw != chess.NoDrawRule) {
+				return chess.NoSquare, chess.Move{}, true, choice
+			}
+			if choice >= 0 && choice < len(pieces) {
+				return pieces[choice], chess.Move{}, true, choice
 			}
+			return chess.NoSquare, chess.Move{}, false, choice
 		}
+
+		// Square of a piece - other squares are read as pawn moves, such as e4
+		if space, err := chess.ParseSquare(command); err == nil && position.PieceAt(space).Color == position.Turn() {
+			return space, chess.Move{}, len(position.LegalMovesFrom(space)) > 0, 0
+		}
+
+		// Whole move
+		if move, ok := parse_move(position, command); ok {
+			return move.From, move, true, typedMoveChoice
+		}
+		return chess.NoSquare, chess.Move{}, false, 0
 	}
-	return validMoves
 }
 
-func select_promotion(piece Piece) (Piece, bool) {
-	isValid, newPiece := true, Piece{}
+// select_promotion returns no piece type once the player backs out.
+func select_promotion() (chess.PieceType, bool) {
 	println("=== Available Promotions  ===")
 	println("1: \tKnight \t\t 2:\tBishop")
 	println("3: \tRook \t\t 4: \tQueen")
 
-	choice := get_input("Select what to promote the pawn to")
-	println(choice)
+	choice, ok := get_input("Select what to promote the pawn to")
+	if !ok {
+		return chess.NoPieceType, true
+	}
 
 	switch choice {
 	case 1:
-		newPiece = define_piece(Knight, piece.player, piece.rank, piece.file)
+		return chess.Knight, true
 	case 2:
-		newPiece = define_piece(Bishop, piece.player, piece.rank, piece.file)
+		return chess.Bishop, true
 	case 3:
-		newPiece = define_piece(Rook, piece.player, piece.rank, piece.file)
+		return chess.Rook, true
 	case 4:
-		newPiece = define_piece(Queen, piece.player, piece.rank, piece.file)
-	default:
-		newPiece = Piece{}
-		isValid = false
+		return chess.Queen, true
 	}
-
-	return newPiece, isValid
+	return chess.NoPieceType, false
 }
 
-func get_moves(piece Piece, board [8][8]Piece, lastMove [3]int, isInCheck bool) [][3]int {
-	moves := make([][3]int, 0)
-	switch piece.pieceType {
-	case Pawn:
-		// Pawn Movement
-		if piece.player == White {
-			// White Forward Moves
-			if board[piece.rank+1][piece.file].player == Blank {
-				if piece.rank+1 == 7 {
-					// Pawn promotion
-					moves = append(moves, [3]int{piece.rank + 1, piece.file, 4})
-				} else {
-					moves = append(moves, [3]int{piece.rank + 1, piece.file, 0})
-				}
-			}
-			if piece.firstMove && board[piece.rank+1][piece.file].player == Blank && board[piece.rank+2][piece.file].player == Blank {
-				// First move check
-				moves = append(moves, [3]int{piece.rank + 2, piece.file, 0})
-			}
-
-			// White Attack Moves
-			if piece.file+1 < 8 {
-				if board[piece.rank+1][piece.file+1].player == Black {
-					if piece.rank+1 == 7 {
-						// Pawn promotion
-						moves = append(moves, [3]int{piece.rank + 1, piece.file + 1, 4})
-					} else {
-						moves = append(moves, [3]int{piece.rank + 1, piece.file + 1, 0})
-					}
-				}
-			}
-			if piece.file-1 >= 0 {
-				if board[piece.rank+1][piece.file-1].player == Black {
-					if piece.rank+1 == 7 {
-						// Pawn promotion
-						moves = append(moves, [3]int{piece.rank + 1, piece.file - 1, 4})
-					} else {
-						moves = append(moves, [3]int{piece.rank + 1, piece.file - 1, 0})
-					}
-				}
-			}
-		} else if piece.player == Black {
-			// Black Forward Moves
-			if board[piece.rank-1][piece.file].player == Blank {
-				if piece.rank-1 == 0 {
-					// Pawn promotion
-					moves = append(moves, [3]int{piece.rank - 1, piece.file, 4})
-				} else {
-					moves = append(moves, [3]int{piece.rank - 1, piece.file, 0})
-				}
-			}
-			if piece.firstMove && board[piece.rank-1][piece.file].player == Blank && board[piece.rank-2][piece.file].player == Blank {
-				// First move check
-				moves = append(moves, [3]int{piece.rank - 2, piece.file, 0})
-			}
-
-			// Black Attack Moves
-			if piece.file+1 < 8 {
-				if board[piece.rank-1][piece.file+1].player == White {
-					if piece.rank-1 == 0 {
-						// Pawn promotion
-						moves = append(moves, [3]int{piece.rank - 1, piece.file + 1, 4})
-					} else {
-						moves = append(moves, [3]int{piece.rank - 1, piece.file + 1, 0})
-					}
-				}
-			}
-			if piece.file-1 >= 0 {
-				if board[piece.rank-1][piece.file-1].player == White {
-					if piece.rank-1 == 0 {
-						// Pawn promotion
-						moves = append(moves, [3]int{piece.rank - 1, piece.file - 1, 4})
-					} else {
-						moves = append(moves, [3]int{piece.rank - 1, piece.file - 1, 0})
-					}
-				}
-			}
-		}
-		// En passant
-		if piece.player == White && lastMove[0] == 4 && board[lastMove[0]][lastMove[1]].pieceType == Pawn && piece.rank == 4 && (piece.file == lastMove[1]+1 || piece.file == lastMove[1]-1) {
-			moves = append(moves, [3]int{lastMove[0] + 1, lastMove[1], 1})
-		}
-		if piece.player == Black && lastMove[0] == 3 && board[lastMove[0]][lastMove[1]].pieceType == Pawn && piece.rank == 3 && (piece.file == lastMove[1]+1 || piece.file == lastMove[1]-1) {
-			moves = append(moves, [3]int{lastMove[0] - 1, lastMove[1], 1})
-		}
-	case Rook:
-		// Rook Movement
-		for up := piece.rank + 1; up < len(board); up++ {
-			moves = append(moves, [3]int{up, piece.file, 0})
-			if board[up][piece.file].player != Blank {
-				break
-			}
-		}
-		for down := piece.rank - 1; down >= 0; down-- {
-			moves = append(moves, [3]int{down, piece.file, 0})
-			if board[down][piece.file].player != Blank {
-				break
-			}
-		}
-		for left := piece.file + 1; left < len(board[piece.rank]); left++ {
-			moves = append(moves, [3]int{piece.rank, left, 0})
-			if board[piece.rank][left].player != Blank {
-				break
-			}
-		}
-		for right := piece.file - 1; right >= 0; right-- {
-			moves = append(moves, [3]int{piece.rank, right, 0})
-			if board[piece.rank][right].player != Blank {
-				break
-			}
-		}
-	case Knight:
-		// Knight Movement
-		moves = append(moves, [3]int{piece.rank + 1, piece.file + 2, 0})
-		moves = append(moves, [3]int{piece.rank + 2, piece.file + 1, 0})
-		moves = append(moves, [3]int{piece.rank + 1, piece.file - 2, 0})
-		moves = append(moves, [3]int{piece.rank + 2, piece.file - 1, 0})
-		moves = append(moves, [3]int{piece.rank - 1, piece.file + 2, 0})
-		moves = append(moves, [3]int{piece.rank - 2, piece.file + 1, 0})
-		moves = append(moves, [3]int{piece.rank - 1, piece.file - 2, 0})
-		moves = append(moves, [3]int{piece.rank - 2, piece.file - 1, 0})
-	case Bishop:
-		// Bishop Movement
-		upleft, upright, downleft, downright := true, true, true, true
-		for count := 1; count < len(board)-1; count++ {
-			if upleft {
-				trank, tfile := piece.rank+count, piece.file+count
-				if trank < 8 && tfile < 8 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						upleft = false
-					}
-				} else {
-					upleft = false
-				}
-			}
-			if upright {
-				trank, tfile := piece.rank-count, piece.file+count
-				if trank >= 0 && tfile < 8 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						upright = false
-					}
-				} else {
-					upright = false
-				}
-			}
-			if downleft {
-				trank, tfile := piece.rank+count, piece.file-count
-				if (trank) < 8 && (tfile) >= 0 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						downleft = false
-					}
-				} else {
-					downleft = false
-				}
-			}
-			if downright {
-				trank, tfile := piece.rank-count, piece.file-count
-				if (trank) >= 0 && (tfile) >= 0 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, piece.file - count, 0})
-					} else {
-						moves = append(moves, [3]int{trank, piece.file - count, 0})
-						downright = false
-					}
-				} else {
-					downright = false
-				}
-			}
-		}
-	case Queen:
-		// Queen Movement
-		// Rook like movement
-		for up := piece.rank + 1; up < len(board); up++ {
-			moves = append(moves, [3]int{up, piece.file, 0})
-			if board[up][piece.file].player != Blank {
-				break
-			}
-		}
-		for down := piece.rank - 1; down >= 0; down-- {
-			moves = append(moves, [3]int{down, piece.file, 0})
-			if board[down][piece.file].player != Blank {
-				break
-			}
-		}
-		for left := piece.file + 1; left < len(board[piece.rank]); left++ {
-			moves = append(moves, [3]int{piece.rank, left, 0})
-			if board[piece.rank][left].player != Blank {
-				break
-			}
-		}
-		for right := piece.file - 1; right >= 0; right-- {
-			moves = append(moves, [3]int{piece.rank, right, 0})
-			if board[piece.rank][right].player != Blank {
-				break
-			}
-		}
-
-		// Bishop like movement
-		upleft, upright, downleft, downright := true, true, true, true
-		for count := 1; count < len(board)-1; count++ {
-			if upleft {
-				trank, tfile := piece.rank+count, piece.file+count
-				if trank < 8 && tfile < 8 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						upleft = false
-					}
-				} else {
-					upleft = false
-				}
-			}
-			if upright {
-				trank, tfile := piece.rank-count, piece.file+count
-				if trank >= 0 && tfile < 8 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						upright = false
-					}
-				} else {
-					upright = false
-				}
-			}
-			if downleft {
-				trank, tfile := piece.rank+count, piece.file-count
-				if (trank) < 8 && (tfile) >= 0 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, tfile, 0})
-					} else {
-						moves = append(moves, [3]int{trank, tfile, 0})
-						downleft = false
-					}
-				} else {
-					downleft = false
-				}
-			}
-			if downright {
-				trank, tfile := piece.rank-count, piece.file-count
-				if (trank) >= 0 && (tfile) >= 0 {
-					if board[trank][tfile].player == Blank {
-						moves = append(moves, [3]int{trank, piece.file - count, 0})
-					} else {
-						moves = append(moves, [3]int{trank, piece.file - count, 0})
-						downright = false
-					}
-				} else {
-					downright = false
-				}
-			}
-		}
-	case King:
-		// King Movement
-		kingMoves := make([][3]int, 0)
-		kingMoves = append(kingMoves, [3]int{piece.rank + 1, piece.file, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank + 1, piece.file + 1, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank, piece.file + 1, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank - 1, piece.file + 1, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank - 1, piece.file, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank - 1, piece.file - 1, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank, piece.file - 1, 0})
-		kingMoves = append(kingMoves, [3]int{piece.rank + 1, piece.file - 1, 0})
-
-		// Castle Movement - King Side
-		if piece.firstMove && board[piece.rank][7].firstMove && !isInCheck {
-			if board[piece.rank][piece.file+1].player == Blank && board[piece.rank][piece.file+2].player == Blank {
-				kingMoves = append(moves, [3]int{piece.rank, piece.file + 2, 2})
-			}
+func prompt_promotion() chess.PieceType {
+	isValidPromotion, retry, promotion := false, false, chess.NoPieceType
+	for {
+		if isValidPromotion {
+			break
 		}
-
-		// Castle Movement - Queen Side
-		if piece.firstMove && board[piece.rank][0].firstMove && !isInCheck {
-			if board[piece.rank][piece.file-1].player == Blank && board[piece.rank][piece.file-2].player == Blank && board[piece.rank][piece.file-3].player == Blank {
-				kingMoves = append(moves, [3]int{piece.rank, piece.file - 2, 3})
-			}
-		}
-
-		// Confirm the move doesn't leave the king in check
-		for k := 0; k < len(kingMoves); k++ {
-			tempBoard := board
-			enemyColor := Blank
-			if piece.player == White {
-				enemyColor = Black
-			} else {
-				enemyColor = White
-			}
-
-			if kingMoves[k][0] < 8 && kingMoves[k][0] >= 0 && kingMoves[k][1] < 8 && kingMoves[k][1] >= 0 {
-				tempBoard = move_piece(tempBoard, piece, kingMoves[k])
-				stillInCheck, _ := check_check(tempBoard, enemyColor)
-
-				if !stillInCheck {
-					moves = append(moves, kingMoves[k])
-				}
-			}
+		if retry {
+			println("ERROR: Invalid promotion. Please choose another promotion.\n")
 		}
+		promotion, isValidPromotion = select_promotion()
+		retry = true
 	}
-	return moves
+	return promotion
 }
 
-func select_move(moves [][3]int) ([3]int, bool) {
+func select_move(position *chess.Position, moves []chess.Move) (chess.Move, bool, bool) {
 	println("\n  === Available Moves ===")
 	for m := 0; m < len(moves); m++ {
-		print(m, ": to ", get_space_format([2]int{moves[m][0], moves[m][1]}), "\t")
+		print(m, ": ", position.SAN(moves[m]), "\t\t")
 		if m%2 == 1 {
 			println()
 		}
 	}
-	move := get_input("\nSelect a move to make")
+	println("\nback: \tChoose another piece")
+	command, ok := get_command("\nSelect a move to make")
 	println()
-	if move >= 0 && move < len(moves) {
-		return moves[move], true
+	if !ok || command == "back" {
+		return chess.Move{}, false, true
 	}
-	return [3]int{}, false
-}
 
-func move_piece(board [8][8]Piece, piece Piece, move [3]int) [8][8]Piece {
-	if move[2] == 1 {
-		// En Passat pawn removal
-		board[piece.rank][move[1]] = Piece{}
-	}
-	if move[2] == 2 {
-		// Castle - King Side
-		board[piece.rank][7] = Piece{}
-		board[piece.rank][piece.file+1] = define_piece(Rook, piece.player, piece.rank, piece.file+1)
-		board[piece.rank][piece.file+1].firstMove = false
-	}
-	if move[2] == 3 {
-		// Castle - Queen Side
-		board[piece.rank][0] = Piece{}
-		board[piece.rank][piece.file-1] = define_piece(Rook, piece.player, piece.rank, piece.file-1)
-		board[piece.rank][piece.file-1].firstMove = false
-	}
-	if move[2] == 4 {
-		// Pawn promotion
-		isValidPromotion, flag, newPiece := false, false, Piece{}
-		for {
-			if isValidPromotion {
-				break
-			}
-			if flag {
-				println("ERROR: Invalid promotion. Please choose another promotion.\n")
-			}
-			newPiece, isValidPromotion = select_promotion(piece)
-			flag = true
+	// Menu index, destination square or whole move
+	moveIndex := -1
+	if move, err := strconv.Atoi(command); err == nil {
+		moveIndex = move
+	} else if space, err := chess.ParseSquare(command); err == nil {
+		if isMove, move := is_move(space, moves); isMove {
+			moveIndex = move
 		}
-		piece = newPiece
+	} else if move, ok := parse_move(position, command); ok && move.From == moves[0].From {
+		return move, true, false
 	}
-	board[piece.rank][piece.file] = Piece{}
-	piece.rank = move[0]
-	piece.file = move[1]
-
-	if piece.firstMove {
-		piece.firstMove = false
+	if moveIndex >= 0 && moveIndex < len(moves) {
+		move := moves[moveIndex]
+		if move.IsPromotion() {
+			move.Promotion = prompt_promotion()
+		}
+		return move, move.IsPromotion() || !moves[moveIndex].IsPromotion(), false
 	}
+	return chess.Move{}, false, false
+}
 
-	board[move[0]][move[1]] = piece
-	return board
+// confirm_capture warns before a capture that loses material in the exchange that follows it
+func confirm_capture(position *chess.Position, move chess.Move) bool {
+	if !move.IsCapture() {
+		return true
+	}
+	if loss := position.SEE(move); loss < 0 {
+		fmt.Printf("WARNING: %v loses material - about %.1f pawns once the exchange is over.\n", position.SAN(move), float64(-loss)/100)
+		return get_confirmation("Play it anyway? [y/N]", false)
+	}
+	return true
 }
 
-func do_turn(board [8][8]Piece, player playerColor, lastMove [3]int, isInCheck bool, blockMoves [][2]int, enemyMoves [][3]int) ([8][8]Piece, bool, [3]int, bool, [][2]int) {
-	isValid, flag := false, false
-	var pieceChoice Piece
-	var moveChoice [3]int
+func do_turn(game *chess.Game) bool {
+	isValid, isBack, retry, choice := false, false, false, 0
+	var pieceChoice chess.Square
+	var moveChoice chess.Move
+	position := game.Position()
 
 	// Select Piece
-	println("\n\n===", player.String(), "Turn ===\n\n")
+	println("\n\n===", game.Turn().String(), "Turn - Move", position.FullmoveNumber(), "===\n\n")
+	if moves := game.MovesSAN(); len(moves) > 0 {
+		println("Last move:", moves[len(moves)-1], "\n")
+	}
 
-	if isInCheck {
+	if game.InCheck() {
 		println("!!! YOU ARE IN CHECK !!! \n")
 	}
 
-	print_board(board, make([][3]int, 0), Piece{})
+	print_board(position, make([]chess.Move, 0), chess.NoSquare)
 	for {
-		if isValid {
-			break
+		isValid, isBack, retry = false, false, false
+		for {
+			if isValid {
+				break
+			}
+			if retry {
+				println("ERROR: Invalid piece. Please choose another piece.\n")
+			}
+			pieceChoice, moveChoice, isValid, choice = select_piece(game, false, get_valid_pieces(game), game.ClaimableDraw())
+			retry = true
 		}
-		if flag {
-			println("ERROR: Invalid piece. Please choose another piece.\n")
+		if choice == resignChoice {
+			game.Resign(game.Turn())
+			return true
+		}
+		if choice == quitChoice {
+			println("\nInput closed - resigning the game.")
+			game.Resign(game.Turn())
+			return true
+		}
+		if choice == claimDrawChoice {
+			return game.ClaimDraw() == nil
+		}
+		if choice == typedMoveChoice {
+			if confirm_capture(position, moveChoice) {
+				break
+			}
+			continue
 		}
-		pieceOptions := get_valid_pieces(board, player, lastMove, isInCheck, blockMoves, enemyMoves)
-		pieceChoice, isValid = select_piece(player, false, pieceOptions)
-		flag = true
-	}
 
-	// Display Moves or Redo turn
-	moveOptions := get_moves(pieceChoice, board, lastMove, isInCheck)
-	moveOptions = get_valid_moves(board, moveOptions, pieceChoice, isInCheck, enemyMoves, blockMoves, lastMove)
-	if len(moveOptions) != 0 {
-		print_board(board, moveOptions, pieceChoice)
+		// Display Moves - promotions to every piece share one entry
+		moveOptions := make([]chess.Move, 0)
+		for _, move := range position.LegalMovesFrom(pieceChoice) {
+			if move.Promotion == chess.NoPieceType || move.Promotion == chess.Queen {
+				moveOptions = append(moveOptions, move)
+			}
+		}
+		print_board(position, moveOptions, pieceChoice)
 
-		// Select Move
-		isValid, flag = false, false
+		// Select Move - going back picks a new piece
+		isValid, retry = false, false
 		for {
-			if isValid {
+			if isValid || isBack {
 				break
 			}
-			if flag {
+			if retry {
 				println("ERROR: Invalid move. Please choose another move.\n")
 			}
-			moveChoice, isValid = select_move(moveOptions)
-			flag = true
+			moveChoice, isValid, isBack = select_move(position, moveOptions)
+			retry = true
 		}
+		if !isBack && confirm_capture(position, moveChoice) {
+			break
+		}
+		print_board(position, make([]chess.Move, 0), chess.NoSquare)
+	}
 
-		// Move Piece
-		board = move_piece(board, pieceChoice, moveChoice)
-		lastMove = moveChoice
-		print_board(board, make([][3]int, 0), pieceChoice)
+	// Move Piece
+	if err := game.Apply(moveChoice); err != nil {
+		println("ERROR:", err.Error())
+		return false
+	}
+	print_board(game.Position(), make([]chess.Move, 0), moveChoice.To)
 
-		isCheck, blockMoves := check_check(board, pieceChoice.player)
+	return true
+}
 
-		return board, true, lastMove, isCheck, blockMoves
+// run_perft counts the move tree below a position: chess perft [-fen FEN] [-divide] depth
+func run_perft(args []string) {
+	perftFlags := flag.NewFlagSet("perft", flag.ExitOnError)
+	fen := perftFlags.String("fen", chess.StartingFEN, "count from the position in this FEN")
+	divide := perftFlags.Bool("divide", false, "break the count down by root move")
+	perftFlags.Parse(args)
+	depth, err := strconv.Atoi(perftFlags.Arg(0))
+	if err != nil || perftFlags.NArg() != 1 || depth < 1 {
+		log.Fatal("usage: chess perft [-fen FEN] [-divide] depth")
+	}
+	position, err := chess.ParseFEN(*fen)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	return board, false, lastMove, false, blockMoves
+	start := time.Now()
+	var nodes uint64
+	if *divide {
+		for _, count := range position.Divide(depth) {
+			fmt.Printf("%v: %v\n", count.Move, count.Nodes)
+			nodes += count.Nodes
+		}
+		fmt.Println()
+	} else {
+		nodes = position.Perft(depth)
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("Nodes: %v\nTime: %v\nNodes/second: %.0f\n", nodes, elapsed.Round(time.Millisecond), float64(nodes)/elapsed.Seconds())
 }
 
-func check_check(board [8][8]Piece, player playerColor) (bool, [][2]int) {
-	isCheck := false
-	kingSpace := [2]int{}
-	allMoves := [][3]int{}
-	movePieces := make([]Piece, 0)
-	checkerPiece := Piece{}
-	blockSpaces := make([][2]int, 0)
-
-	for r := len(board) - 1; r >= 0; r-- {
-		for f := len(board[r]) - 1; f >= 0; f-- {
-			if board[r][f].pieceType == King && board[r][f].player != player {
-				// Found enemy king
-				kingSpace = [2]int{r, f}
-			} else if board[r][f].player == player && board[r][f].pieceType != King {
-				// Add moves to catalog of current available moves
-				tempMoves := get_moves(board[r][f], board, [3]int{0, 0, 0}, false)
-				for m := 0; m < len(tempMoves); m++ {
-					allMoves = append(allMoves, tempMoves[m])
-					movePieces = append(movePieces, board[r][f])
-				}
+// run_eval prints the engine's evaluation of a position term by term: chess eval [-weights FILE] [-fen FEN]
+func run_eval(args []string) {
+	evalFlags := flag.NewFlagSet("eval", flag.ExitOnError)
+	fen := evalFlags.String("fen", chess.StartingFEN, "evaluate the position in this FEN")
+	weightsPath := evalFlags.String("weights", "", "JSON file of evaluation weights to use instead of the defaults")
+	evalFlags.Parse(args)
+	if evalFlags.NArg() != 0 {
+		log.Fatal("usage: chess eval [-weights FILE] [-fen FEN]")
+	}
+	load_weights(*weightsPath)
+	position, err := chess.ParseFEN(*fen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	evaluation := engine.Evaluate(position)
+	fmt.Print(evaluation.String())
+}
 
-			}
-		}
+// load_weights replaces the engine's evaluation weights with those in the file at path, if one is given
+func load_weights(path string) {
+	if path == "" {
+		return
+	}
+	weights, err := engine.LoadWeights(path)
+	if err != nil {
+		log.Fatal(err)
 	}
+	engine.DefaultWeights = weights
+}
 
-	for m := 0; m < len(allMoves); m++ {
-		if allMoves[m][0] == kingSpace[0] && allMoves[m][1] == kingSpace[1] {
-			// Checks all moves against kings location for check
-			isCheck = true
-			checkerPiece = movePieces[m]
+// format_score shows an engine score in pawns, or as the moves to a mate
+func format_score(score int) string {
+	if engine.IsMateScore(score) {
+		if score > 0 {
+			return fmt.Sprintf("mate in %d", (engine.Mate-score+1)/2)
 		}
+		return fmt.Sprintf("mated in %d", (engine.Mate+score)/2)
 	}
+	return fmt.Sprintf("%+.2f", float64(score)/100)
+}
 
-	switch checkerPiece.pieceType {
-	case Pawn, Knight:
-		blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank, checkerPiece.file})
-	case Bishop:
-		count := checkerPiece.rank - kingSpace[0]
-		if count < 0 {
-			count *= -1
-		}
-		if checkerPiece.rank < kingSpace[0] && checkerPiece.file < kingSpace[1] {
-			for s := 0; s <= count; s++ {
-				blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file + s})
-			}
-		} else if checkerPiece.rank < kingSpace[0] && checkerPiece.file > kingSpace[1] {
-			for s := 0; s <= count; s++ {
-				blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file - s})
-			}
-		} else if checkerPiece.rank > kingSpace[0] && checkerPiece.file < kingSpace[1] {
-			for s := 0; s <= count; s++ {
-				blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file + s})
-			}
-		} else if checkerPiece.rank > kingSpace[0] && checkerPiece.file > kingSpace[1] {
-			for s := 0; s <= count; s++ {
-				blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file - s})
-			}
-		}
-	case Rook:
-		if checkerPiece.rank == kingSpace[0] {
-			count := checkerPiece.file - kingSpace[1]
-			if count < 0 {
-				count *= -1
-			}
-			if checkerPiece.file < kingSpace[1] {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank, checkerPiece.file + s})
-				}
-			} else {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank, checkerPiece.file - s})
-				}
-			}
-		} else {
-			count := checkerPiece.rank - kingSpace[0]
-			if count < 0 {
-				count *= -1
-			}
-			if checkerPiece.rank <= kingSpace[0] {
-				for s := 0; s < count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file})
-				}
-			} else {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file})
-				}
-			}
-		}
-	case Queen:
-		if checkerPiece.rank != kingSpace[0] && checkerPiece.file != kingSpace[1] {
-			// Bishop Moves
-			count := checkerPiece.rank - kingSpace[0]
-			if count < 0 {
-				count *= -1
-			}
-			if checkerPiece.rank < kingSpace[0] && checkerPiece.file < kingSpace[1] {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file + s})
-				}
-			} else if checkerPiece.rank < kingSpace[0] && checkerPiece.file > kingSpace[1] {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file - s})
-				}
-			} else if checkerPiece.rank > kingSpace[0] && checkerPiece.file < kingSpace[1] {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file + s})
-				}
-			} else if checkerPiece.rank > kingSpace[0] && checkerPiece.file > kingSpace[1] {
-				for s := 0; s <= count; s++ {
-					blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file - s})
-				}
-			}
-		} else {
-			// Rook Moves
-			if checkerPiece.rank == kingSpace[0] {
-				count := checkerPiece.file - kingSpace[1]
-				if count < 0 {
-					count *= -1
-				}
-				if checkerPiece.file < kingSpace[1] {
-					for s := 0; s <= count; s++ {
-						blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank, checkerPiece.file + s})
-					}
-				} else {
-					for s := 0; s <= count; s++ {
-						blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank, checkerPiece.file - s})
-					}
-				}
-			} else {
-				count := checkerPiece.rank - kingSpace[0]
-				if count < 0 {
-					count *= -1
-				}
-				if checkerPiece.rank < kingSpace[0] {
-					for s := 0; s <= count; s++ {
-						blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank + s, checkerPiece.file})
-					}
-				} else {
-					for s := 0; s <= count; s++ {
-						blockSpaces = append(blockSpaces, [2]int{checkerPiece.rank - s, checkerPiece.file})
-					}
-				}
-			}
-		}
-	default:
+func engine_turn(game *chess.Game, player *engine.Engine, limits engine.Limits) {
+	position := game.Position()
+	println("\n\n===", game.Turn().String(), "Turn - Move", position.FullmoveNumber(), "===\n\n")
+	print_board(position, make([]chess.Move, 0), chess.NoSquare)
+	println("\nThinking...")
 
+	result := player.BestMove(position, limits)
+	stats := player.Table().Stats()
+	san := position.SAN(result.Move)
+	if err := game.Apply(result.Move); err != nil {
+		log.Fatal(err)
 	}
-
-	return isCheck, blockSpaces
+	fmt.Printf("Engine plays %v (%v, depth %v, %v nodes, %.0f%% hash hits)\n", san, format_score(result.Score), result.Depth, result.Nodes, 100*stats.HitRate())
+	print_board(game.Position(), make([]chess.Move, 0), result.Move.To)
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "perft" {
+		run_perft(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "eval" {
+		run_eval(os.Args[2:])
+		return
+	}
+
 	// Game Setup
-	Board := initialize_board() // TODO : This can be refactors to remove the space coloring if i don't end up using space colors
-	Board = fill_board(Board)
-	isTurnValid, flag, isCheck := false, false, false
-	player := White
-	lastMove := [3]int{}
-	blockMoves := make([][2]int, 0)
-	enemyMoves := make([][3]int, 0)
-
-	// Game Loop
+	fen := flag.String("fen", chess.StartingFEN, "start from the position in this FEN")
+	white := flag.String("white", "human", "who plays White: human or engine")
+	black := flag.String("black", "human", "who plays Black: human or engine")
+	depth := flag.Int("depth", 0, "plies the engine searches, 0 for no limit")
+	moveTime := flag.Duration("movetime", 3*time.Second, "time the engine thinks per move, 0 for no limit")
+	weightsPath := flag.String("weights", "", "JSON file of evaluation weights to use instead of the defaults")
+	hashSize := flag.Int("hash", engine.DefaultHashSize, "megabytes of memory the engine remembers positions in")
+	flag.Parse()
+	load_weights(*weightsPath)
+	position, err := chess.ParseFEN(*fen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	players := map[chess.Color]string{chess.White: *white, chess.Black: *black}
+	for _, player := range players {
+		if player != "human" && player != "engine" {
+			log.Fatalf("unknown player %q: want human or engine", player)
+		}
+	}
+	limits := engine.Limits{Depth: *depth, MoveTime: *moveTime}
+	var player *engine.Engine
+	if *white == "engine" || *black == "engine" {
+		player = engine.New(*hashSize)
+	}
+	if err := open_console(); err != nil {
+		log.Fatal(err)
+	}
+	defer console.Close()
+	game := chess.NewGameFromPosition(position)
+	game.SetTag("Date", time.Now().Format("2006.01.02"))
+	if *white == "engine" {
+		game.SetTag("White", "Engine")
+	}
+	if *black == "engine" {
+		game.SetTag("Black", "Engine")
+	}
+	isTurnValid, retry := false, false
+
+	// Game Loop - a position set up from FEN may already be over
 	for {
-		isTurnValid, flag = false, false
+		if game.Outcome().IsOver() {
+			break
+		}
+		if players[game.Turn()] == "engine" {
+			engine_turn(game, player, limits)
+			continue
+		}
+
+		isTurnValid, retry = false, false
 		for {
 			if isTurnValid {
 				break
 			}
-			if flag {
+			if retry {
 				println("ERROR: Invalid turn. Please choose another piece.\n")
 			}
-			Board, isTurnValid, lastMove, isCheck, blockMoves = do_turn(Board, player, lastMove, isCheck, blockMoves, enemyMoves)
-			flag = true
-		}
-
-		if player == White {
-			player = Black
-		} else {
-			player = White
-		}
-
-		// Index all enemy moves
-		for r := len(Board) - 1; r >= 0; r-- {
-			for f := len(Board[r]) - 1; f >= 0; f-- {
-				if Board[r][f].player != player && Board[r][f].player != Blank {
-					tempMoves := get_moves(Board[r][f], Board, lastMove, false)
-					enemyMoves = append(enemyMoves, get_valid_moves(Board, tempMoves, Board[r][f], false, make([][3]int, 0), make([][2]int, 0), lastMove)...)
-				}
-			}
-		}
-
-		if len(get_valid_pieces(Board, player, lastMove, isCheck, blockMoves, enemyMoves)) == 0 {
-			// Current player has won if this is reached
-			break
+			isTurnValid = do_turn(game)
+			retry = true
 		}
 	}
 
-	if player == White {
-		player = Black
+	result := game.Outcome()
+	if result.IsDraw() {
+		println("\n\n\n\n\n=== GAME DRAWN:", result.String(), "===")
 	} else {
-		player = White
+		println("\n\n\n\n\n=== CONGRATS ON THE WIN:", result.String(), "===")
+	}
+	if get_confirmation("Save the game to "+*pgnPath+"? [Y/n]", true) {
+		save_game(game, *pgnPath)
 	}
-	println("\n\n\n\n\n=== CONGRATS ON THE WIN: ", player.String(), "===")
-
-	// TODO : Fix weird issues with Get_valid_pieces() letting no valid pieces through
-	// TODO : Fix castle moves not being available when they should be
-	// TODO : Add ability to cancel piece selection
-	// TODO : Add ability to choose pieces and mvoes by space instead of the index
 }
@@ -0,0 +1,197 @@
+package chess
+
+var (
+	knightJumps = [8][2]int{{1, 2}, {2, 1}, {1, -2}, {2, -1}, {-1, 2}, {-2, 1}, {-1, -2}, {-2, -1}}
+	kingSteps   = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	rookRays    = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+	bishopRays  = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+)
+
+// Attack tables, filled in by init.
+var (
+	knightAttacks [64]Bitboard
+	kingAttacks   [64]Bitboard
+	pawnAttacks   [3][64]Bitboard
+
+	// between holds the squares strictly between two squares on a shared
+	// rank, file or diagonal, and line the whole of that rank, file or
+	// diagonal. Both are empty for squares that don't share one.
+	between [64][64]Bitboard
+	line    [64][64]Bitboard
+
+	rookMagics   [64]magic
+	bishopMagics [64]magic
+)
+
+// magic finds the attacks of a slider in a table shared by every square. The
+// occupied squares the slider could be blocked by are multiplied by a number
+// chosen so their top bits are a perfect hash of the blockers that matter.
+type magic struct {
+	mask    Bitboard
+	number  uint64
+	shift   uint
+	attacks []Bitboard
+}
+
+func (m *magic) index(occupied Bitboard) uint64 {
+	return uint64(occupied&m.mask) * m.number >> m.shift
+}
+
+func init() {
+	for sq := A1; sq <= H8; sq++ {
+		for _, d := range knightJumps {
+			if to := sq.offset(d[0], d[1]); to.IsValid() {
+				knightAttacks[sq] |= to.Bitboard()
+			}
+		}
+		for _, d := range kingSteps {
+			if to := sq.offset(d[0], d[1]); to.IsValid() {
+				kingAttacks[sq] |= to.Bitboard()
+			}
+		}
+		for _, df := range [2]int{-1, 1} {
+			if to := sq.offset(1, df); to.IsValid() {
+				pawnAttacks[White][sq] |= to.Bitboard()
+			}
+			if to := sq.offset(-1, df); to.IsValid() {
+				pawnAttacks[Black][sq] |= to.Bitboard()
+			}
+		}
+	}
+
+	for from := A1; from <= H8; from++ {
+		for _, rays := range [2][4][2]int{rookRays, bishopRays} {
+			for _, d := range rays {
+				var ray Bitboard
+				for to := from.offset(d[0], d[1]); to.IsValid(); to = to.offset(d[0], d[1]) {
+					between[from][to] = ray
+					ray |= to.Bitboard()
+				}
+				for to := from.offset(d[0], d[1]); to.IsValid(); to = to.offset(d[0], d[1]) {
+					line[from][to] = ray | slideAttacks(from, 0, [][2]int{{-d[0], -d[1]}}) | from.Bitboard()
+				}
+			}
+		}
+	}
+
+	for sq := A1; sq <= H8; sq++ {
+		rookMagics[sq] = newMagic(sq, rookRays[:], rookMagicNumbers[sq])
+		bishopMagics[sq] = newMagic(sq, bishopRays[:], bishopMagicNumbers[sq])
+	}
+}
+
+// slideAttacks returns the squares a slider on sq moving along rays attacks,
+// stopping at the first occupied square in each direction. It is the slow way
+// that the magic tables are built from.
+func slideAttacks(sq Square, occupied Bitboard, rays [][2]int) Bitboard {
+	var attacks Bitboard
+	for _, d := range rays {
+		for to := sq.offset(d[0], d[1]); to.IsValid(); to = to.offset(d[0], d[1]) {
+			attacks |= to.Bitboard()
+			if occupied.Has(to) {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+// newMagic builds the attack table of a slider on sq moving along rays,
+// indexed with the magic number given.
+func newMagic(sq Square, rays [][2]int, number uint64) magic {
+	// Blockers on the edge of the board never shorten a ray
+	edges := ((Rank1 | Rank8) &^ RankMask(sq.Rank())) | ((FileA | FileH) &^ FileMask(sq.File()))
+	mask := slideAttacks(sq, 0, rays) &^ edges
+	bitCount := uint(mask.Count())
+	m := magic{mask: mask, number: number, shift: 64 - bitCount, attacks: make([]Bitboard, 1<<bitCount)}
+
+	// Every subset of the mask must map to a slot holding its attacks
+	filled := make([]bool, len(m.attacks))
+	for subset := Bitboard(0); ; {
+		attacks, index := slideAttacks(sq, subset, rays), m.index(subset)
+		if filled[index] && m.attacks[index] != attacks {
+			panic("chess: bad magic number for " + sq.String())
+		}
+		m.attacks[index], filled[index] = attacks, true
+		subset = (subset - mask) & mask
+		if subset == 0 {
+			break
+		}
+	}
+	return m
+}
+
+// Magic numbers, found by trying sparse random numbers until one mapped every
+// arrangement of blockers on the square to a slot holding the right attacks.
+var rookMagicNumbers = [64]uint64{
+	0x008000908064C000, 0x0040200040001000, 0x0180100080A0010A, 0x8880041000800800,
+	0x1200100201200804, 0x0200020004011008, 0x2180010000800600, 0x0200005088210204,
+	0x0400800040008021, 0x0400400020005000, 0x8240801000200080, 0x8611001004200900,
+	0x008180800C001800, 0x0100800200800400, 0x0A02000102000408, 0x8020802300104280,
+	0x0080004000402000, 0xE010104000402000, 0x0800808010002000, 0xA280210008100100,
+	0x0001818014000800, 0xA002010100080400, 0x0080240001020870, 0x0001020004048845,
+	0x0081826280004004, 0x2020810900284000, 0x0200100080802000, 0x0200080080100080,
+	0x8083080100100500, 0x4406000901000400, 0x0005020080800100, 0x0090204200008114,
+	0x0010400094800420, 0x0900804000802002, 0x0201001841002000, 0x4100080080801000,
+	0x4540040080800800, 0x0002001004040020, 0x0281195814001002, 0x1240800040800100,
+	0x0880042000524004, 0x02C080410206002C, 0x0801200241050010, 0x8400080010008080,
+	0x0008000500090010, 0x0082009084020008, 0x4012000108020004, 0x9000104D08860004,
+	0x2004204114800100, 0x0148802112400300, 0x0202842000100880, 0x001B080080900080,
+	0x001A002008100600, 0x0004008004020080, 0x5181000600040300, 0x0000044401128A00,
+	0x8044110480002441, 0x2008110084402202, 0x90806005090010C1, 0x000420310A004A42,
+	0x0023001004020801, 0x0882001008040102, 0x000230088118020C, 0x0000019025040042,
+}
+
+var bishopMagicNumbers = [64]uint64{
+	0x0045010808008680, 0x2002080204004898, 0x0210009A10400006, 0x0824050200810200,
+	0x0006061105004090, 0x00010108C0000000, 0x0814040282104004, 0x0012012201106800,
+	0x10823014100C1040, 0x0080C2088802808C, 0x0281108410404000, 0x0101212041826200,
+	0x0020141028221058, 0x2201020202200202, 0x000082A801482000, 0x0000008401411044,
+	0x0007103014300404, 0x0002091110010100, 0x42140012040C0808, 0x0800808802004020,
+	0x90C4004210140000, 0x0800200900A01000, 0x00D0400201108810, 0x80820183814412A0,
+	0x00A01008202202B4, 0x01C2021A09500402, 0x0084440208042400, 0x800400400C090100,
+	0xBA10040010802100, 0xD182009006005000, 0x5011021001009004, 0x0020420200510400,
+	0x0292104000468800, 0x00043009091C0500, 0x0280441000020025, 0x0042820080080080,
+	0x0440101010010040, 0x1000900100808080, 0x0108108120089800, 0x0044010200012682,
+	0xC002500420900400, 0x0040482210710800, 0x0002060024000200, 0x0281020A44000800,
+	0xA0021200A4000200, 0x0001301000840840, 0x2868500108444220, 0x0004111041000200,
+	0x8044020842080200, 0x0000220104210200, 0x0000021201044000, 0x0000280884040028,
+	0x4012114010858003, 0x0000081004082B88, 0x3892700508208002, 0x00220A041B060400,
+	0x0812020284014881, 0x010434A282103100, 0x0490400824020800, 0x4A20002C00208800,
+	0x000000A011020200, 0x4002940A02482202, 0x5100100202140406, 0x02102000840540C1,
+}
+
+// KnightAttacks returns the squares a knight on sq attacks.
+func KnightAttacks(sq Square) Bitboard {
+	return knightAttacks[sq]
+}
+
+// KingAttacks returns the squares a king on sq attacks.
+func KingAttacks(sq Square) Bitboard {
+	return kingAttacks[sq]
+}
+
+// PawnAttacks returns the squares a pawn of color c on sq attacks.
+func PawnAttacks(c Color, sq Square) Bitboard {
+	return pawnAttacks[c][sq]
+}
+
+// RookAttacks returns the squares a rook on sq attacks when the squares in
+// occupied hold pieces.
+func RookAttacks(sq Square, occupied Bitboard) Bitboard {
+	m := &rookMagics[sq]
+	return m.attacks[m.index(occupied)]
+}
+
+// BishopAttacks returns the squares a bishop on sq attacks when the squares
+// in occupied hold pieces.
+func BishopAttacks(sq Square, occupied Bitboard) Bitboard {
+	m := &bishopMagics[sq]
+	return m.attacks[m.index(occupied)]
+}
+
+// QueenAttacks returns the squares a queen on sq attacks when the squares in
+// occupied hold pieces.
+func QueenAttacks(sq Square, occupied Bitboard) Bitboard {
+	return RookAttacks(sq, occupied) | BishopAttacks(sq, occupied)
+}
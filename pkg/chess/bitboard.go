@@ -0,0 +1,85 @@
+package chess
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Bitboard is a set of squares, bit n standing for Square n.
+type Bitboard uint64
+
+// Masks of the first and last files and ranks and of the light squares.
+const (
+	FileA Bitboard = 0x0101010101010101
+	FileH Bitboard = FileA << 7
+	Rank1 Bitboard = 0xFF
+	Rank8 Bitboard = Rank1 << 56
+
+	LightSquares Bitboard = 0x55AA55AA55AA55AA
+)
+
+// FileMask returns the squares of a file, 0 for the a-file through 7.
+func FileMask(file int) Bitboard {
+	return FileA << file
+}
+
+// RankMask returns the squares of a rank, 0 for the first rank through 7.
+func RankMask(rank int) Bitboard {
+	return Rank1 << (8 * rank)
+}
+
+// Bitboard returns the set holding only sq.
+func (sq Square) Bitboard() Bitboard {
+	return 1 << uint(sq)
+}
+
+// Has reports whether sq is in the set.
+func (b Bitboard) Has(sq Square) bool {
+	return b&sq.Bitboard() != 0
+}
+
+// Count returns the number of squares in the set.
+func (b Bitboard) Count() int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// First returns the lowest numbered square in the set, or NoSquare if it is
+// empty.
+func (b Bitboard) First() Square {
+	if b == 0 {
+		return NoSquare
+	}
+	return Square(bits.TrailingZeros64(uint64(b)))
+}
+
+// Squares returns the squares in the set, lowest first.
+func (b Bitboard) Squares() []Square {
+	squares := make([]Square, 0, b.Count())
+	for ; b != 0; b &= b - 1 {
+		squares = append(squares, b.First())
+	}
+	return squares
+}
+
+// String draws the set as a board from White's side, x for the squares in it.
+func (b Bitboard) String() string {
+	var s strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		for file := 0; file < 8; file++ {
+			if b.Has(NewSquare(file, rank)) {
+				s.WriteByte('x')
+			} else {
+				s.WriteByte('.')
+			}
+		}
+		s.WriteByte('\n')
+	}
+	return s.String()
+}
+
+// pop removes the lowest numbered square from the set and returns it.
+func (b *Bitboard) pop() Square {
+	sq := Square(bits.TrailingZeros64(uint64(*b)))
+	*b &= *b - 1
+	return sq
+}
@@ -0,0 +1,78 @@
+package chess
+
+// CastlingRights records which castling moves each side may still make. A
+// right is lost for good once the king or that wing's rook moves or the rook
+// is captured.
+type CastlingRights struct {
+	WhiteKingSide  bool
+	WhiteQueenSide bool
+	BlackKingSide  bool
+	BlackQueenSide bool
+}
+
+// AllCastlingRights is the castling state at the start of a game.
+var AllCastlingRights = CastlingRights{true, true, true, true}
+
+// CanCastle reports whether c still holds the right to castle on the king
+// side or the queen side.
+func (cr CastlingRights) CanCastle(c Color, kingSide bool) bool {
+	switch c {
+	case White:
+		if kingSide {
+			return cr.WhiteKingSide
+		}
+		return cr.WhiteQueenSide
+	case Black:
+		if kingSide {
+			return cr.BlackKingSide
+		}
+		return cr.BlackQueenSide
+	}
+	return false
+}
+
+func (cr CastlingRights) String() string {
+	rights := ""
+	if cr.WhiteKingSide {
+		rights += "K"
+	}
+	if cr.WhiteQueenSide {
+		rights += "Q"
+	}
+	if cr.BlackKingSide {
+		rights += "k"
+	}
+	if cr.BlackQueenSide {
+		rights += "q"
+	}
+	if rights == "" {
+		return "-"
+	}
+	return rights
+}
+
+// update removes the rights a move of piece from from to to gives up.
+func (cr CastlingRights) update(piece Piece, from, to Square) CastlingRights {
+	if piece.Type == King {
+		if piece.Color == White {
+			cr.WhiteKingSide, cr.WhiteQueenSide = false, false
+		} else {
+			cr.BlackKingSide, cr.BlackQueenSide = false, false
+		}
+	}
+
+	// A rook leaving its corner, or anything landing on it, ends castling on that wing
+	for _, sq := range [2]Square{from, to} {
+		switch sq {
+		case H1:
+			cr.WhiteKingSide = false
+		case A1:
+			cr.WhiteQueenSide = false
+		case H8:
+			cr.BlackKingSide = false
+		case A8:
+			cr.BlackQueenSide = false
+		}
+	}
+	return cr
+}
@@ -0,0 +1,90 @@
+// Package chess implements the rules of chess: the board and its pieces, legal
+// move generation, check detection and game termination.
+//
+// The package never reads input or prints output. Front ends such as the CLI
+// in the repository root drive a Game by asking it for legal moves and
+// applying the ones their players choose.
+package chess
+
+// Color is the side a piece belongs to. NoColor marks an empty square and the
+// winner of a drawn or unfinished game.
+type Color uint8
+
+const (
+	NoColor Color = iota
+	White
+	Black
+)
+
+// Other returns the opposing side. NoColor has no opponent.
+func (c Color) Other() Color {
+	switch c {
+	case White:
+		return Black
+	case Black:
+		return White
+	}
+	return NoColor
+}
+
+func (c Color) String() string {
+	switch c {
+	case White:
+		return "White"
+	case Black:
+		return "Black"
+	}
+	return ""
+}
+
+// PieceType is the kind of a piece regardless of its color.
+type PieceType uint8
+
+const (
+	NoPieceType PieceType = iota
+	Pawn
+	Rook
+	Knight
+	Bishop
+	Queen
+	King
+)
+
+func (t PieceType) String() string {
+	switch t {
+	case Pawn:
+		return "pawn"
+	case Rook:
+		return "rook"
+	case Knight:
+		return "knight"
+	case Bishop:
+		return "bishop"
+	case Queen:
+		return "queen"
+	case King:
+		return "king"
+	}
+	return ""
+}
+
+// Piece is a piece on the board. The zero Piece is an empty square.
+type Piece struct {
+	Type  PieceType
+	Color Color
+}
+
+// NoPiece is the contents of an empty square.
+var NoPiece = Piece{}
+
+// IsEmpty reports whether the piece is the empty square.
+func (p Piece) IsEmpty() bool {
+	return p.Type == NoPieceType
+}
+
+func (p Piece) String() string {
+	if p.IsEmpty() {
+		return ""
+	}
+	return p.Color.String() + " " + p.Type.String()
+}
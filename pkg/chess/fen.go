@@ -0,0 +1,197 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StartingFEN is the FEN of the standard initial position.
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var fenPieces = map[byte]Piece{
+	'P': {Pawn, White}, 'N': {Knight, White}, 'B': {Bishop, White},
+	'R': {Rook, White}, 'Q': {Queen, White}, 'K': {King, White},
+	'p': {Pawn, Black}, 'n': {Knight, Black}, 'b': {Bishop, Black},
+	'r': {Rook, Black}, 'q': {Queen, Black}, 'k': {King, Black},
+}
+
+func fenError(format string, args ...any) error {
+	return fmt.Errorf("chess: invalid FEN: "+format, args...)
+}
+
+// ParseFEN returns the position described by a Forsyth-Edwards Notation
+// string. The halfmove clock and fullmove number may be left off, in which
+// case they default to 0 and 1. The position must be one that could arise in
+// a game: one king each, no pawns on the first or last rank, castling rights
+// only for kings and rooks on their starting squares, and the side not to
+// move not in check.
+func ParseFEN(fen string) (*Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 4 && len(fields) != 6 {
+		return nil, fenError("want 4 or 6 fields, got %d", len(fields))
+	}
+	p := &Position{enPassant: NoSquare, fullmove: 1, undo: make([]undo, 0, 64)}
+
+	// Piece placement, from rank 8 down to rank 1 and the a-file to the h-file
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fenError("piece placement has %d ranks, want 8", len(ranks))
+	}
+	for i, rankText := range ranks {
+		rank, squares := 7-i, 0
+		for j := 0; j < len(rankText); j++ {
+			c := rankText[j]
+			if c >= '1' && c <= '8' {
+				squares += int(c - '0')
+				continue
+			}
+			piece, ok := fenPieces[c]
+			if !ok {
+				return nil, fenError("unknown piece %q on rank %d", c, rank+1)
+			}
+			if squares < 8 {
+				p.setPiece(NewSquare(squares, rank), piece)
+			}
+			squares++
+		}
+		if squares != 8 {
+			return nil, fenError("rank %d has %d squares, want 8", rank+1, squares)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		p.turn = White
+	case "b":
+		p.turn = Black
+	default:
+		return nil, fenError("side to move %q is not w or b", fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, c := range fields[2] {
+			switch c {
+			case 'K':
+				p.castling.WhiteKingSide = true
+			case 'Q':
+				p.castling.WhiteQueenSide = true
+			case 'k':
+				p.castling.BlackKingSide = true
+			case 'q':
+				p.castling.BlackQueenSide = true
+			default:
+				return nil, fenError("unknown castling right %q", c)
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		sq, err := ParseSquare(fields[3])
+		if err != nil {
+			return nil, fenError("en passant target %q is not a square", fields[3])
+		}
+		p.enPassant = sq
+	}
+
+	if len(fields) == 6 {
+		halfmove, err := strconv.Atoi(fields[4])
+		if err != nil || halfmove < 0 {
+			return nil, fenError("halfmove clock %q is not a number of moves", fields[4])
+		}
+		fullmove, err := strconv.Atoi(fields[5])
+		if err != nil || fullmove < 1 {
+			return nil, fenError("fullmove number %q is not a move number", fields[5])
+		}
+		p.halfmoveClock, p.fullmove = halfmove, fullmove
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	p.hash = p.computeHash()
+	return p, nil
+}
+
+// validate checks that the position could arise in a game.
+func (p *Position) validate() error {
+	if pawns := (p.pieces[White][Pawn] | p.pieces[Black][Pawn]) & (Rank1 | Rank8); pawns != 0 {
+		return fenError("pawn on %s", pawns.First())
+	}
+	if white, black := p.pieces[White][King].Count(), p.pieces[Black][King].Count(); white != 1 || black != 1 {
+		return fenError("want one king each, got %d white and %d black", white, black)
+	}
+
+	for _, c := range [2]Color{White, Black} {
+		homeRank := 0
+		if c == Black {
+			homeRank = 7
+		}
+		if (p.castling.CanCastle(c, true) || p.castling.CanCastle(c, false)) && p.PieceAt(NewSquare(4, homeRank)) != (Piece{King, c}) {
+			return fenError("%v may castle but its king has moved", c)
+		}
+		if p.castling.CanCastle(c, true) && p.PieceAt(NewSquare(7, homeRank)) != (Piece{Rook, c}) {
+			return fenError("%v may castle king side but its h-file rook has moved", c)
+		}
+		if p.castling.CanCastle(c, false) && p.PieceAt(NewSquare(0, homeRank)) != (Piece{Rook, c}) {
+			return fenError("%v may castle queen side but its a-file rook has moved", c)
+		}
+	}
+
+	if p.enPassant.IsValid() {
+		// The target is behind a pawn of the side not to move that just advanced two squares
+		forward, targetRank := -1, 5
+		if p.turn == Black {
+			forward, targetRank = 1, 2
+		}
+		if p.enPassant.Rank() != targetRank || p.PieceAt(p.enPassant.offset(forward, 0)) != (Piece{Pawn, p.turn.Other()}) {
+			return fenError("en passant target %s does not follow a two square pawn advance", p.enPassant)
+		}
+	}
+
+	king, _ := p.kingSquare(p.turn.Other())
+	if p.isAttacked(king, p.turn) {
+		return fenError("%v is in check but it is %v's move", p.turn.Other(), p.turn)
+	}
+	return nil
+}
+
+// FEN returns the Forsyth-Edwards Notation of the position.
+func (p *Position) FEN() string {
+	var b strings.Builder
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := p.board[NewSquare(file, rank)]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				b.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			c := " PRNBQK"[piece.Type]
+			if piece.Color == Black {
+				c += 'a' - 'A'
+			}
+			b.WriteByte(c)
+		}
+		if empty > 0 {
+			b.WriteString(strconv.Itoa(empty))
+		}
+		if rank > 0 {
+			b.WriteByte('/')
+		}
+	}
+
+	turn := "w"
+	if p.turn == Black {
+		turn = "b"
+	}
+	enPassant := "-"
+	if p.enPassant.IsValid() {
+		enPassant = p.enPassant.String()
+	}
+	return fmt.Sprintf("%s %s %s %s %d %d", b.String(), turn, p.castling, enPassant, p.halfmoveClock, p.fullmove)
+}
@@ -0,0 +1,63 @@
+package chess
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFEN(t *testing.T) {
+	for _, fen := range []string{
+		StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2",
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 11 42",
+	} {
+		p, err := ParseFEN(fen)
+		if err != nil {
+			t.Errorf("%s: %v", fen, err)
+		} else if p.FEN() != fen {
+			t.Errorf("%s: written back as %s", fen, p.FEN())
+		}
+	}
+
+	// The clocks may be left off
+	if p, err := ParseFEN("4k3/8/8/8/8/8/8/4K3 w - -"); err != nil || p.FEN() != "4k3/8/8/8/8/8/8/4K3 w - - 0 1" {
+		t.Errorf("FEN without clocks: got %v, %v", p, err)
+	}
+}
+
+func TestParseFENErrors(t *testing.T) {
+	for _, want := range []struct {
+		fen, err string
+	}{
+		{"", "want 4 or 6 fields, got 0"},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0", "want 4 or 6 fields, got 5"},
+		{"4k3/8/8/8/8/8/4K3 w - - 0 1", "piece placement has 7 ranks, want 8"},
+		{"4k3/8/8/8/8/8/8/4K4 w - - 0 1", "rank 1 has 9 squares, want 8"},
+		{"4k3/8/8/8/8/8/8/4K2 w - - 0 1", "rank 1 has 7 squares, want 8"},
+		{"4k3/8/8/8/8/8/8/4KK3 w - - 0 1", "rank 1 has 9 squares, want 8"},
+		{"4k3/8/8/8/8/8/8/4X3 w - - 0 1", `unknown piece 'X' on rank 1`},
+		{"4k3/8/8/8/8/8/8/4K3 x - - 0 1", `side to move "x" is not w or b`},
+		{"4k3/8/8/8/8/8/8/4K3 w X - 0 1", `unknown castling right 'X'`},
+		{"4k3/8/8/8/8/8/8/4K3 w - e9 0 1", `en passant target "e9" is not a square`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - -1 1", `halfmove clock "-1" is not a number of moves`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 0", `fullmove number "0" is not a move number`},
+		{"4k3/8/8/8/8/8/8/P3K3 w - - 0 1", "pawn on a1"},
+		{"p3k3/8/8/8/8/8/8/4K3 w - - 0 1", "pawn on a8"},
+		{"8/8/8/8/8/8/8/4K3 w - - 0 1", "want one king each, got 1 white and 0 black"},
+		{"4k3/8/8/8/8/8/8/3KK3 w - - 0 1", "want one king each, got 2 white and 1 black"},
+		{"4k3/8/8/8/8/8/8/R2K3R w K - 0 1", "White may castle but its king has moved"},
+		{"4k3/8/8/8/8/8/8/R3K3 w K - 0 1", "White may castle king side but its h-file rook has moved"},
+		{"1r2k3/8/8/8/8/8/8/4K3 w q - 0 1", "Black may castle queen side but its a-file rook has moved"},
+		{"4k3/8/8/8/4P3/8/8/4K3 b - e4 0 1", "en passant target e4 does not follow a two square pawn advance"},
+		{"4k3/8/8/8/8/8/8/4K3 b - e3 0 1", "en passant target e3 does not follow a two square pawn advance"},
+		{"4k3/8/8/8/4p3/8/8/4K3 w - e6 0 1", "en passant target e6 does not follow a two square pawn advance"},
+		{"4k3/8/8/8/8/8/8/4K2r b - - 0 1", "White is in check but it is Black's move"},
+	} {
+		_, err := ParseFEN(want.fen)
+		if err == nil || !strings.HasPrefix(err.Error(), "chess: invalid FEN: ") || !strings.Contains(err.Error(), want.err) {
+			t.Errorf("%q: got error %v, want %q", want.fen, err, want.err)
+		}
+	}
+}
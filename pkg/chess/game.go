@@ -0,0 +1,223 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrGameOver    = errors.New("chess: game is over")
+	ErrIllegalMove = errors.New("chess: illegal move")
+	ErrNoDrawClaim = errors.New("chess: no draw can be claimed")
+)
+
+// Game is a game in progress: the position it started from, the current
+// position, the moves played to reach it and their SAN, the positions seen so
+// far for repetition, any result decided off the board and its PGN tags.
+type Game struct {
+	startFEN  string
+	position  *Position
+	moves     []Move
+	san       []string
+	positions map[uint64]int
+	result    Result
+	tags      []Tag
+}
+
+// NewGame returns a game starting from the standard initial position.
+func NewGame() *Game {
+	return NewGameFromPosition(StartingPosition())
+}
+
+// NewGameFromPosition returns a game starting from p, such as a position
+// parsed with ParseFEN. The game takes ownership of p.
+func NewGameFromPosition(p *Position) *Game {
+	g := &Game{startFEN: p.FEN(), position: p, positions: make(map[uint64]int)}
+	g.positions[g.position.key()]++
+	return g
+}
+
+// Position returns the current position.
+func (g *Game) Position() *Position {
+	return g.position
+}
+
+// Turn returns the side to move.
+func (g *Game) Turn() Color {
+	return g.position.Turn()
+}
+
+// Moves returns the moves played so far, oldest first.
+func (g *Game) Moves() []Move {
+	return append([]Move(nil), g.moves...)
+}
+
+// MovesSAN returns the moves played so far in Standard Algebraic Notation,
+// oldest first.
+func (g *Game) MovesSAN() []string {
+	return append([]string(nil), g.san...)
+}
+
+// LegalMoves returns every legal move in the current position, or none once
+// the game is over.
+func (g *Game) LegalMoves() []Move {
+	if g.Outcome().IsOver() {
+		return nil
+	}
+	return g.position.LegalMoves()
+}
+
+// InCheck reports whether the side to move is in check.
+func (g *Game) InCheck() bool {
+	return g.position.InCheck()
+}
+
+// Apply plays the legal move from m.From to m.To, promoting to m.Promotion.
+// Only those fields of m need to be filled in; the rest is taken from the
+// matching legal move.
+func (g *Game) Apply(m Move) error {
+	if g.Outcome().IsOver() {
+		return ErrGameOver
+	}
+	legal, ok := findMove(g.position.LegalMovesFrom(m.From), m)
+	if !ok {
+		return ErrIllegalMove
+	}
+	g.play(legal)
+	return nil
+}
+
+// play makes the legal move m and records it, whether or not the game is
+// over.
+func (g *Game) play(m Move) {
+	g.san = append(g.san, g.position.SAN(m))
+	g.position.MakeMove(m)
+	g.moves = append(g.moves, m)
+	g.positions[g.position.key()]++
+}
+
+// ApplySAN plays the move written in Standard Algebraic Notation, accepting
+// the same sloppy forms as Position.ParseSAN.
+func (g *Game) ApplySAN(san string) error {
+	if g.Outcome().IsOver() {
+		return ErrGameOver
+	}
+	m, err := g.position.ParseSAN(san)
+	if err != nil {
+		return err
+	}
+	return g.Apply(m)
+}
+
+// Resign ends the game as a loss for c.
+func (g *Game) Resign(c Color) {
+	if !g.Outcome().IsOver() {
+		g.result = Result{Winner: c.Other(), Reason: Resignation}
+	}
+}
+
+// Timeout ends the game as a loss for c, whose clock ran out.
+func (g *Game) Timeout(c Color) {
+	if !g.Outcome().IsOver() {
+		g.result = Result{Winner: c.Other(), Reason: Timeout}
+	}
+}
+
+// AgreeDraw ends the game as a draw agreed by both players.
+func (g *Game) AgreeDraw() {
+	if !g.Outcome().IsOver() {
+		g.result = Result{Reason: Agreement}
+	}
+}
+
+// setRecordedResult ends the game with a result recorded elsewhere, such as a
+// PGN result token. A result the position has already decided must match the
+// token, unless the token is *. Otherwise decisive results are taken as
+// resignations unless the Termination tag says the loser ran out of time, and
+// draws as agreed unless one could be claimed.
+func (g *Game) setRecordedResult(token string) error {
+	if outcome := g.Outcome(); outcome.IsOver() {
+		if token != "*" && token != outcome.PGN() {
+			return fmt.Errorf("result %s contradicts the position: %v", token, outcome)
+		}
+		return nil
+	}
+	switch token {
+	case "1-0", "0-1":
+		loser := Black
+		if token == "0-1" {
+			loser = White
+		}
+		if g.tag("Termination") == "time forfeit" {
+			g.Timeout(loser)
+		} else {
+			g.Resign(loser)
+		}
+	case "1/2-1/2":
+		if g.ClaimDraw() != nil {
+			g.AgreeDraw()
+		}
+	}
+	return nil
+}
+
+// ClaimableDraw returns the rule the side to move may claim a draw under, or
+// NoDrawRule.
+func (g *Game) ClaimableDraw() DrawRule {
+	if g.positions[g.position.key()] >= 3 {
+		return ThreefoldRepetition
+	}
+	if g.position.halfmoveClock >= 100 {
+		return FiftyMoveRule
+	}
+	return NoDrawRule
+}
+
+// ClaimDraw ends the game as a draw if the side to move may claim one.
+func (g *Game) ClaimDraw() error {
+	if g.Outcome().IsOver() {
+		return ErrGameOver
+	}
+	rule := g.ClaimableDraw()
+	if rule == NoDrawRule {
+		return ErrNoDrawClaim
+	}
+	g.result = Result{Reason: DrawByRule, Rule: rule}
+	return nil
+}
+
+// Outcome returns the result of the game. Checkmate and stalemate are found on
+// the board, as are the draws that need no claim: fivefold repetition, the
+// seventy-five-move rule and insufficient material.
+func (g *Game) Outcome() Result {
+	if g.result.IsOver() {
+		return g.result
+	}
+
+	p := g.position
+	if len(p.LegalMoves()) == 0 {
+		if p.InCheck() {
+			return Result{Winner: p.turn.Other(), Reason: Checkmate}
+		}
+		return Result{Reason: Stalemate}
+	}
+	if p.hasInsufficientMaterial() {
+		return Result{Reason: DrawByRule, Rule: InsufficientMaterial}
+	}
+	if g.positions[p.key()] >= 5 {
+		return Result{Reason: DrawByRule, Rule: FivefoldRepetition}
+	}
+	if p.halfmoveClock >= 150 {
+		return Result{Reason: DrawByRule, Rule: SeventyFiveMoveRule}
+	}
+	return Result{}
+}
+
+func findMove(moves []Move, m Move) (Move, bool) {
+	for _, legal := range moves {
+		if legal.From == m.From && legal.To == m.To && legal.Promotion == m.Promotion {
+			return legal, true
+		}
+	}
+	return Move{}, false
+}
@@ -0,0 +1,125 @@
+package chess
+
+// undo is what MakeMove saves to restore the position it changed. The move
+// itself records the piece it captured.
+type undo struct {
+	move          Move
+	castling      CastlingRights
+	enPassant     Square
+	halfmoveClock int
+	hash          uint64
+}
+
+// MakeMove plays m on the position in place. The move must be pseudo-legal,
+// as returned by LegalMoves; nothing else is checked.
+func (p *Position) MakeMove(m Move) {
+	p.undo = append(p.undo, undo{m, p.castling, p.enPassant, p.halfmoveClock, p.hash})
+	p.hash ^= p.enPassantKey()
+
+	rank := m.From.Rank()
+	switch {
+	case m.Has(EnPassant):
+		p.removePiece(NewSquare(m.To.File(), rank))
+	case m.Has(CastleKingSide):
+		// The h-file rook jumps to the king's other side
+		p.movePiece(NewSquare(7, rank), m.To-1)
+	case m.Has(CastleQueenSide):
+		// The a-file rook jumps to the king's other side
+		p.movePiece(NewSquare(0, rank), m.To+1)
+	case m.IsCapture():
+		p.removePiece(m.To)
+	}
+
+	p.removePiece(m.From)
+	if m.IsPromotion() {
+		p.setPiece(m.To, Piece{m.Promotion, m.Piece.Color})
+	} else {
+		p.setPiece(m.To, m.Piece)
+	}
+
+	p.enPassant = NoSquare
+	if m.Has(DoublePush) {
+		p.enPassant = (m.From + m.To) / 2
+	}
+
+	p.hash ^= castlingKey(p.castling)
+	p.castling = p.castling.update(m.Piece, m.From, m.To)
+	p.hash ^= castlingKey(p.castling)
+
+	// Pawn moves and captures reset the clock for the 50 and 75 move rules
+	if m.Piece.Type == Pawn || m.IsCapture() {
+		p.halfmoveClock = 0
+	} else {
+		p.halfmoveClock++
+	}
+
+	if p.turn == Black {
+		p.fullmove++
+	}
+	p.turn = p.turn.Other()
+	p.hash ^= zobristTurn ^ p.enPassantKey()
+}
+
+// UnmakeMove takes back the last move made with MakeMove. It does nothing if
+// no moves have been made.
+func (p *Position) UnmakeMove() {
+	if len(p.undo) == 0 {
+		return
+	}
+	u := p.undo[len(p.undo)-1]
+	p.undo = p.undo[:len(p.undo)-1]
+	m := u.move
+
+	// The hash is restored whole below, so the piece helpers' updates to it don't matter
+	rank := m.From.Rank()
+	if m.IsPromotion() {
+		p.removePiece(m.To)
+		p.setPiece(m.From, m.Piece)
+	} else {
+		p.movePiece(m.To, m.From)
+	}
+	switch {
+	case m.Has(EnPassant):
+		p.setPiece(NewSquare(m.To.File(), rank), m.Captured)
+	case m.Has(CastleKingSide):
+		p.movePiece(m.To-1, NewSquare(7, rank))
+	case m.Has(CastleQueenSide):
+		p.movePiece(m.To+1, NewSquare(0, rank))
+	case m.IsCapture():
+		p.setPiece(m.To, m.Captured)
+	}
+
+	p.turn = p.turn.Other()
+	if p.turn == Black {
+		p.fullmove--
+	}
+	p.castling = u.castling
+	p.enPassant = u.enPassant
+	p.halfmoveClock = u.halfmoveClock
+	p.hash = u.hash
+}
+
+// setPiece puts piece on the empty square sq.
+func (p *Position) setPiece(sq Square, piece Piece) {
+	bb := sq.Bitboard()
+	p.board[sq] = piece
+	p.pieces[piece.Color][piece.Type] |= bb
+	p.colors[piece.Color] |= bb
+	p.hash ^= pieceKey(piece, sq)
+}
+
+func (p *Position) removePiece(sq Square) {
+	if piece := p.board[sq]; !piece.IsEmpty() {
+		bb := sq.Bitboard()
+		p.board[sq] = NoPiece
+		p.pieces[piece.Color][piece.Type] &^= bb
+		p.colors[piece.Color] &^= bb
+		p.hash ^= pieceKey(piece, sq)
+	}
+}
+
+func (p *Position) movePiece(from, to Square) {
+	piece := p.board[from]
+	p.removePiece(from)
+	p.setPiece(to, piece)
+}
@@ -0,0 +1,111 @@
+package chess
+
+// MoveFlag marks the moves that do more than move one piece from one square
+// to another.
+type MoveFlag uint8
+
+const (
+	// DoublePush is a pawn's two square advance from its starting rank.
+	DoublePush MoveFlag = 1 << iota
+	// EnPassant captures the pawn beside From rather than a piece on To.
+	EnPassant
+	// CastleKingSide also moves the h-file rook over the king.
+	CastleKingSide
+	// CastleQueenSide also moves the a-file rook over the king.
+	CastleQueenSide
+)
+
+// Move is a move of Piece from From to To. Captured is the piece taken, which
+// for en passant is the pawn beside From, and Promotion is the piece a pawn
+// reaching the last rank becomes.
+type Move struct {
+	From      Square
+	To        Square
+	Piece     Piece
+	Captured  Piece
+	Promotion PieceType
+	Flags     MoveFlag
+}
+
+// Has reports whether every flag in f is set on the move.
+func (m Move) Has(f MoveFlag) bool {
+	return m.Flags&f == f
+}
+
+// IsCapture reports whether the move takes a piece.
+func (m Move) IsCapture() bool {
+	return !m.Captured.IsEmpty()
+}
+
+// IsPromotion reports whether the move promotes a pawn.
+func (m Move) IsPromotion() bool {
+	return m.Promotion != NoPieceType
+}
+
+// IsCastle reports whether the move castles on either wing.
+func (m Move) IsCastle() bool {
+	return m.Flags&(CastleKingSide|CastleQueenSide) != 0
+}
+
+// String formats the move in UCI long algebraic notation, such as e2e4 or
+// e7e8q. The zero Move is the null move, 0000.
+func (m Move) String() string {
+	if m == (Move{}) {
+		return "0000"
+	}
+	uci := m.From.String() + m.To.String()
+	switch m.Promotion {
+	case Knight:
+		uci += "n"
+	case Bishop:
+		uci += "b"
+	case Rook:
+		uci += "r"
+	case Queen:
+		uci += "q"
+	}
+	return uci
+}
+
+// PackedMove is a Move packed into 32 bits for compact storage:
+//
+//	bits  0-5   from square
+//	bits  6-11  to square
+//	bits 12-14  moved piece type
+//	bit  15     set when the moved piece is Black
+//	bits 16-18  captured piece type, of the other color
+//	bits 19-21  promotion piece type
+//	bits 22-25  flags
+type PackedMove uint32
+
+// Pack returns the compact encoding of the move.
+func (m Move) Pack() PackedMove {
+	packed := uint32(m.From) |
+		uint32(m.To)<<6 |
+		uint32(m.Piece.Type)<<12 |
+		uint32(m.Captured.Type)<<16 |
+		uint32(m.Promotion)<<19 |
+		uint32(m.Flags)<<22
+	if m.Piece.Color == Black {
+		packed |= 1 << 15
+	}
+	return PackedMove(packed)
+}
+
+// Unpack returns the move the encoding was packed from.
+func (pm PackedMove) Unpack() Move {
+	m := Move{
+		From:      Square(pm & 63),
+		To:        Square(pm >> 6 & 63),
+		Piece:     Piece{PieceType(pm >> 12 & 7), White},
+		Promotion: PieceType(pm >> 19 & 7),
+		Flags:     MoveFlag(pm >> 22 & 15),
+	}
+	if pm&(1<<15) != 0 {
+		m.Piece.Color = Black
+	}
+	if captured := PieceType(pm >> 16 & 7); captured != NoPieceType {
+		m.Captured = Piece{captured, m.Piece.Color.Other()}
+	}
+	return m
+}
@@ -0,0 +1,201 @@
+package chess
+
+// LegalMoves returns every legal move for the side to move.
+func (p *Position) LegalMoves() []Move {
+	return p.AppendLegalMoves(make([]Move, 0, 64))
+}
+
+// LegalMovesFrom returns the legal moves of the piece on sq. It is empty if
+// the square is empty or holds a piece of the side not to move.
+func (p *Position) LegalMovesFrom(sq Square) []Move {
+	if p.PieceAt(sq).Color != p.turn {
+		return nil
+	}
+	var legal []Move
+	for _, m := range p.LegalMoves() {
+		if m.From == sq {
+			legal = append(legal, m)
+		}
+	}
+	return legal
+}
+
+// AppendLegalMoves appends every legal move for the side to move to moves
+// and returns the extended slice. Search code can reuse one slice per ply to
+// generate moves without allocating.
+func (p *Position) AppendLegalMoves(moves []Move) []Move {
+	us, them := p.turn, p.turn.Other()
+	king, ok := p.kingSquare(us)
+	if !ok {
+		return moves
+	}
+	own, enemy := p.colors[us], p.colors[them]
+	occupied := own | enemy
+
+	// The king may go to any square not attacked once it has stepped off its
+	// own, so sliders checking it along a line still cover the square behind it
+	for targets := kingAttacks[king] &^ own; targets != 0; {
+		to := targets.pop()
+		if p.attackers(to, them, occupied^king.Bitboard()) == 0 {
+			moves = append(moves, Move{From: king, To: to, Piece: p.board[king], Captured: p.board[to]})
+		}
+	}
+
+	// Only the king can escape a double check. Otherwise the other pieces
+	// must capture a checker or block its line.
+	checkers := p.attackers(king, them, occupied)
+	if checkers.Count() > 1 {
+		return moves
+	}
+	checkMask := ^Bitboard(0)
+	if checkers != 0 {
+		checker := checkers.First()
+		checkMask = checkers | between[king][checker]
+	} else {
+		moves = p.appendCastlingMoves(moves, king)
+	}
+
+	// A pinned piece stays on the line between its king and the pinner
+	pinned := p.pinned(king, us)
+	allowed := func(from Square) Bitboard {
+		if pinned.Has(from) {
+			return checkMask & line[king][from]
+		}
+		return checkMask
+	}
+
+	for pieces := p.pieces[us][Knight] &^ pinned; pieces != 0; {
+		from := pieces.pop()
+		moves = p.appendMoves(moves, from, knightAttacks[from]&^own&checkMask)
+	}
+	for pieces := p.pieces[us][Bishop] | p.pieces[us][Queen]; pieces != 0; {
+		from := pieces.pop()
+		moves = p.appendMoves(moves, from, BishopAttacks(from, occupied)&^own&allowed(from))
+	}
+	for pieces := p.pieces[us][Rook] | p.pieces[us][Queen]; pieces != 0; {
+		from := pieces.pop()
+		moves = p.appendMoves(moves, from, RookAttacks(from, occupied)&^own&allowed(from))
+	}
+
+	forward, startRank := 1, 1
+	if us == Black {
+		forward, startRank = -1, 6
+	}
+	for pawns := p.pieces[us][Pawn]; pawns != 0; {
+		from := pawns.pop()
+		mask := allowed(from)
+
+		// Forward moves, two squares from the starting rank
+		if one := from.offset(forward, 0); !occupied.Has(one) {
+			if mask.Has(one) {
+				moves = p.appendPawnMove(moves, from, one, 0)
+			}
+			if two := one.offset(forward, 0); from.Rank() == startRank && !occupied.Has(two) && mask.Has(two) {
+				moves = p.appendPawnMove(moves, from, two, DoublePush)
+			}
+		}
+
+		// Attack moves
+		for targets := pawnAttacks[us][from] & enemy & mask; targets != 0; {
+			moves = p.appendPawnMove(moves, from, targets.pop(), 0)
+		}
+		if p.enPassant.IsValid() && pawnAttacks[us][from].Has(p.enPassant) {
+			m := Move{From: from, To: p.enPassant, Piece: p.board[from], Captured: Piece{Pawn, them}, Flags: EnPassant}
+			if p.isKingSafeAfter(m) {
+				moves = append(moves, m)
+			}
+		}
+	}
+	return moves
+}
+
+// appendMoves adds a move from the piece on from to every square in targets.
+func (p *Position) appendMoves(moves []Move, from Square, targets Bitboard) []Move {
+	piece := p.board[from]
+	for targets != 0 {
+		to := targets.pop()
+		moves = append(moves, Move{From: from, To: to, Piece: piece, Captured: p.board[to]})
+	}
+	return moves
+}
+
+// appendPawnMove adds a pawn move, as four moves when the pawn promotes since
+// each choice of piece is its own move.
+func (p *Position) appendPawnMove(moves []Move, from, to Square, flags MoveFlag) []Move {
+	m := Move{From: from, To: to, Piece: p.board[from], Captured: p.board[to], Flags: flags}
+	if rank := to.Rank(); rank != 0 && rank != 7 {
+		return append(moves, m)
+	}
+	for _, promotion := range [4]PieceType{Queen, Rook, Bishop, Knight} {
+		m.Promotion = promotion
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// pinned returns the pieces of color c that stand alone between their king
+// and an enemy slider on the same line.
+func (p *Position) pinned(king Square, c Color) Bitboard {
+	them := &p.pieces[c.Other()]
+	occupied := p.Occupancy(NoColor)
+	snipers := RookAttacks(king, 0)&(them[Rook]|them[Queen]) | BishopAttacks(king, 0)&(them[Bishop]|them[Queen])
+
+	var pinned Bitboard
+	for snipers != 0 {
+		blockers := between[king][snipers.pop()] & occupied
+		if blockers.Count() == 1 {
+			pinned |= blockers & p.colors[c]
+		}
+	}
+	return pinned
+}
+
+// isKingSafeAfter plays m and reports whether the mover's king is left
+// unattacked. Generation only needs it for en passant, which can remove both
+// pawns from the king's rank at once.
+func (p *Position) isKingSafeAfter(m Move) bool {
+	mover := p.turn
+	p.MakeMove(m)
+	king, ok := p.kingSquare(mover)
+	safe := !ok || !p.isAttacked(king, mover.Other())
+	p.UnmakeMove()
+	return safe
+}
+
+// appendCastlingMoves adds the castling moves of the king on sq. The king may
+// not castle out of, through or into check; the caller has already ruled out
+// the first.
+func (p *Position) appendCastlingMoves(moves []Move, sq Square) []Move {
+	king := p.board[sq]
+	homeRank := 0
+	if king.Color == Black {
+		homeRank = 7
+	}
+	if sq != E1.offset(homeRank, 0) {
+		return moves
+	}
+
+	rook := Piece{Rook, king.Color}
+	occupied := p.Occupancy(NoColor)
+	safe := func(files ...int) bool {
+		for _, f := range files {
+			if p.isAttacked(NewSquare(f, homeRank), king.Color.Other()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// King side - towards the h-file
+	if p.castling.CanCastle(king.Color, true) && p.board[NewSquare(7, homeRank)] == rook &&
+		occupied&between[sq][NewSquare(7, homeRank)] == 0 && safe(5, 6) {
+		moves = append(moves, Move{From: sq, To: NewSquare(6, homeRank), Piece: king, Flags: CastleKingSide})
+	}
+
+	// Queen side - towards the a-file
+	if p.castling.CanCastle(king.Color, false) && p.board[NewSquare(0, homeRank)] == rook &&
+		occupied&between[sq][NewSquare(0, homeRank)] == 0 && safe(3, 2) {
+		moves = append(moves, Move{From: sq, To: NewSquare(2, homeRank), Piece: king, Flags: CastleQueenSide})
+	}
+	return moves
+}
@@ -0,0 +1,55 @@
+package chess
+
+// MoveCount is the number of leaf nodes below one root move, as reported by
+// Divide.
+type MoveCount struct {
+	Move  Move
+	Nodes uint64
+}
+
+// Perft counts the leaf nodes of the legal move tree depth plies below the
+// position. Comparing the counts with known values is the standard check of a
+// move generator.
+func (p *Position) Perft(depth int) uint64 {
+	if depth <= 0 {
+		return 1
+	}
+	buffers := make([][]Move, depth)
+	for i := range buffers {
+		buffers[i] = make([]Move, 0, 256)
+	}
+	return p.perft(depth, buffers)
+}
+
+// perft generates each ply's moves into its own buffer so nothing is
+// allocated while walking the tree. Moves one ply from the leaves are counted
+// rather than made.
+func (p *Position) perft(depth int, buffers [][]Move) uint64 {
+	moves := p.AppendLegalMoves(buffers[depth-1][:0])
+	if depth == 1 {
+		return uint64(len(moves))
+	}
+
+	var nodes uint64
+	for _, m := range moves {
+		p.MakeMove(m)
+		nodes += p.perft(depth-1, buffers)
+		p.UnmakeMove()
+	}
+	return nodes
+}
+
+// Divide breaks Perft down by root move, which narrows a wrong count down to
+// the move whose subtree is off.
+func (p *Position) Divide(depth int) []MoveCount {
+	if depth <= 0 {
+		return nil
+	}
+	var counts []MoveCount
+	for _, m := range p.LegalMoves() {
+		p.MakeMove(m)
+		counts = append(counts, MoveCount{m, p.Perft(depth - 1)})
+		p.UnmakeMove()
+	}
+	return counts
+}
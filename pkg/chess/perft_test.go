@@ -0,0 +1,103 @@
+package chess
+
+import "testing"
+
+// Reference positions and node counts from the Chess Programming Wiki's perft
+// results page. Counts are listed by depth, starting at depth 1.
+var perftPositions = []struct {
+	name  string
+	fen   string
+	nodes []uint64
+}{
+	{"start", StartingFEN, []uint64{20, 400, 8902, 197281, 4865609}},
+	{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", []uint64{48, 2039, 97862, 4085603}},
+	{"position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", []uint64{14, 191, 2812, 43238, 674624}},
+	{"position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", []uint64{6, 264, 9467, 422333}},
+	{"position 4 mirrored", "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1", []uint64{6, 264, 9467, 422333}},
+	{"position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", []uint64{44, 1486, 62379, 2103487}},
+	{"position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10", []uint64{46, 2079, 89890, 3894594}},
+}
+
+// Counts above this many nodes are only checked without -short.
+const perftShortLimit = 100000
+
+func TestPerft(t *testing.T) {
+	for _, pos := range perftPositions {
+		t.Run(pos.name, func(t *testing.T) {
+			p, err := ParseFEN(pos.fen)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range pos.nodes {
+				if testing.Short() && want > perftShortLimit {
+					break
+				}
+				if got := p.Perft(i + 1); got != want {
+					t.Errorf("depth %d: got %d nodes, want %d", i+1, got, want)
+				}
+			}
+			if p.FEN() != pos.fen {
+				t.Errorf("position changed to %s", p.FEN())
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	for _, pos := range perftPositions {
+		p, err := ParseFEN(pos.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := p.Divide(3)
+		var total uint64
+		for _, c := range counts {
+			total += c.Nodes
+		}
+		if len(counts) != int(pos.nodes[0]) || total != pos.nodes[2] {
+			t.Errorf("%s: got %d moves and %d nodes, want %d and %d", pos.name, len(counts), total, pos.nodes[0], pos.nodes[2])
+		}
+	}
+}
+
+// TestPerftMakeUnmake walks the tree to depth 3 checking that every move packs
+// and unpacks unchanged, that the incremental hash matches a fresh one and
+// that unmaking restores the position exactly.
+func TestPerftMakeUnmake(t *testing.T) {
+	var walk func(t *testing.T, p *Position, depth int)
+	walk = func(t *testing.T, p *Position, depth int) {
+		if depth == 0 {
+			return
+		}
+		for _, m := range p.LegalMoves() {
+			if got := m.Pack().Unpack(); got != m {
+				t.Fatalf("%s: %v unpacked as %v", p.FEN(), m, got)
+			}
+			fen := p.FEN()
+			p.MakeMove(m)
+			if p.Hash() != p.computeHash() {
+				t.Fatalf("%s: hash wrong after %v", fen, m)
+			}
+			walk(t, p, depth-1)
+			p.UnmakeMove()
+			if p.FEN() != fen {
+				t.Fatalf("%s: unmaking %v gave %s", fen, m, p.FEN())
+			}
+		}
+	}
+
+	for _, pos := range perftPositions {
+		p, err := ParseFEN(pos.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		walk(t, p, 3)
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	p := StartingPosition()
+	for i := 0; i < b.N; i++ {
+		p.Perft(4)
+	}
+}
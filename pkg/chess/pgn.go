@@ -0,0 +1,146 @@
+package chess
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Tag is a PGN tag pair, such as [Event "Casual game"].
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// sevenTagRoster is the tags every PGN game has, in the order they appear.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// SetTag sets a PGN tag of the game, replacing any earlier value. The Result
+// tag is always taken from the outcome of the game and can't be set.
+func (g *Game) SetTag(name, value string) {
+	for i := range g.tags {
+		if g.tags[i].Name == name {
+			g.tags[i].Value = value
+			return
+		}
+	}
+	g.tags = append(g.tags, Tag{name, value})
+}
+
+// Tags returns the game's PGN tags: the Seven Tag Roster first, unknown
+// values as "?", then SetUp and FEN if the game didn't start from the initial
+// position, then Termination once the game is over, then any other tags in the
+// order they were set.
+func (g *Game) Tags() []Tag {
+	tags := make([]Tag, 0, len(sevenTagRoster)+len(g.tags)+3)
+	for _, name := range sevenTagRoster {
+		value := g.tag(name)
+		switch {
+		case name == "Result":
+			value = g.Outcome().PGN()
+		case name == "Date" && value == "":
+			value = "????.??.??"
+		case value == "":
+			value = "?"
+		}
+		tags = append(tags, Tag{name, value})
+	}
+
+	if g.startFEN != StartingFEN {
+		tags = append(tags, Tag{"SetUp", "1"}, Tag{"FEN", g.startFEN})
+	}
+	if result := g.Outcome(); result.IsOver() && g.tag("Termination") == "" {
+		termination := "normal"
+		if result.Reason == Timeout {
+			termination = "time forfeit"
+		}
+		tags = append(tags, Tag{"Termination", termination})
+	}
+
+	for _, tag := range g.tags {
+		switch tag.Name {
+		case "Event", "Site", "Date", "Round", "White", "Black", "Result", "SetUp", "FEN":
+		default:
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (g *Game) tag(name string) string {
+	for _, tag := range g.tags {
+		if tag.Name == name {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// PGN returns the result token PGN uses for the outcome: 1-0, 0-1, 1/2-1/2,
+// or * while the game is in progress.
+func (r Result) PGN() string {
+	switch {
+	case !r.IsOver():
+		return "*"
+	case r.Winner == White:
+		return "1-0"
+	case r.Winner == Black:
+		return "0-1"
+	}
+	return "1/2-1/2"
+}
+
+// PGN returns the game in Portable Game Notation. Games still in progress are
+// written with the result *.
+func (g *Game) PGN() string {
+	var b strings.Builder
+	g.WritePGN(&b)
+	return b.String()
+}
+
+// WritePGN writes the game in Portable Game Notation to w.
+func (g *Game) WritePGN(w io.Writer) error {
+	var b strings.Builder
+	for _, tag := range g.Tags() {
+		value := strings.ReplaceAll(tag.Value, `\`, `\\`)
+		value = strings.ReplaceAll(value, `"`, `\"`)
+		fmt.Fprintf(&b, "[%s \"%s\"]\n", tag.Name, value)
+	}
+	b.WriteString("\n")
+
+	// Replay the game from its start to write each move in SAN
+	position, err := ParseFEN(g.startFEN)
+	if err != nil {
+		return err
+	}
+	var tokens []string
+	for i, m := range g.moves {
+		if position.turn == White {
+			tokens = append(tokens, strconv.Itoa(position.fullmove)+".")
+		} else if i == 0 {
+			tokens = append(tokens, strconv.Itoa(position.fullmove)+"...")
+		}
+		tokens = append(tokens, position.SAN(m))
+		position.MakeMove(m)
+	}
+	tokens = append(tokens, g.Outcome().PGN())
+
+	// Movetext lines are kept under 80 characters
+	line := 0
+	for i, token := range tokens {
+		if i > 0 && line+1+len(token) >= 80 {
+			b.WriteString("\n")
+			line = 0
+		} else if i > 0 {
+			b.WriteString(" ")
+			line++
+		}
+		b.WriteString(token)
+		line += len(token)
+	}
+	b.WriteString("\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,144 @@
+package chess
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTrip writes g as PGN, reads it back and checks nothing was lost.
+func roundTrip(t *testing.T, g *Game) string {
+	t.Helper()
+	pgn := g.PGN()
+	games, err := ParsePGN(pgn)
+	if err != nil {
+		t.Fatalf("%v reading back\n%s", err, pgn)
+	}
+	if len(games) != 1 {
+		t.Fatalf("got %d games reading back\n%s", len(games), pgn)
+	}
+	read := games[0]
+	if !reflect.DeepEqual(read.Tags(), g.Tags()) {
+		t.Errorf("tags read back as %v, want %v", read.Tags(), g.Tags())
+	}
+	if !reflect.DeepEqual(read.MovesSAN(), g.MovesSAN()) {
+		t.Errorf("moves read back as %v, want %v", read.MovesSAN(), g.MovesSAN())
+	}
+	if read.Outcome() != g.Outcome() {
+		t.Errorf("result read back as %v, want %v", read.Outcome(), g.Outcome())
+	}
+	return pgn
+}
+
+func playSAN(t *testing.T, g *Game, moves ...string) {
+	t.Helper()
+	for _, san := range moves {
+		if err := g.ApplySAN(san); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWritePGN(t *testing.T) {
+	g := NewGame()
+	g.SetTag("Event", "Club \"blitz\" night")
+	g.SetTag("White", `Back\slash`)
+	g.SetTag("Annotator", "Someone")
+	g.SetTag("Result", "1-0")
+	playSAN(t, g, "e4", "e5", "Nf3")
+
+	want := `[Event "Club \"blitz\" night"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "Back\\slash"]
+[Black "?"]
+[Result "*"]
+[Annotator "Someone"]
+
+1. e4 e5 2. Nf3 *
+`
+	if pgn := roundTrip(t, g); pgn != want {
+		t.Errorf("got\n%s\nwant\n%s", pgn, want)
+	}
+}
+
+func TestWritePGNResults(t *testing.T) {
+	for _, want := range []struct {
+		end         func(g *Game)
+		result      string
+		termination string
+	}{
+		{func(g *Game) { playSAN(t, g, "f3", "e5", "g4", "Qh4#") }, "0-1", "normal"},
+		{func(g *Game) { playSAN(t, g, "e4"); g.Resign(Black) }, "1-0", "normal"},
+		{func(g *Game) { playSAN(t, g, "e4"); g.Timeout(White) }, "0-1", "time forfeit"},
+		{func(g *Game) { playSAN(t, g, "e4"); g.AgreeDraw() }, "1/2-1/2", "normal"},
+	} {
+		g := NewGame()
+		want.end(g)
+		pgn := roundTrip(t, g)
+		if !strings.Contains(pgn, `[Result "`+want.result+`"]`) ||
+			!strings.Contains(pgn, `[Termination "`+want.termination+`"]`) ||
+			!strings.HasSuffix(pgn, " "+want.result+"\n") {
+			t.Errorf("want result %s by %s, got\n%s", want.result, want.termination, pgn)
+		}
+	}
+}
+
+func TestWritePGNFromFEN(t *testing.T) {
+	const fen = "4k3/8/8/8/8/8/4P3/4K3 b - - 0 12"
+	p, err := ParseFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGameFromPosition(p)
+	playSAN(t, g, "Kd7", "e4", "Kc6")
+
+	pgn := roundTrip(t, g)
+	if !strings.Contains(pgn, "[SetUp \"1\"]\n[FEN \""+fen+"\"]\n") {
+		t.Errorf("missing SetUp and FEN tags in\n%s", pgn)
+	}
+	if !strings.Contains(pgn, "\n\n12... Kd7 13. e4 Kc6 *\n") {
+		t.Errorf("movetext doesn't start with Black's move number in\n%s", pgn)
+	}
+}
+
+func TestWritePGNWrapsMovetext(t *testing.T) {
+	// A long game that never repeats a position, so nothing ends it early
+	g := NewGame()
+	placement := func() string {
+		return strings.Join(strings.Fields(g.Position().FEN())[:4], " ")
+	}
+	seen := map[string]bool{placement(): true}
+	for len(g.Moves()) < 150 && !g.Outcome().IsOver() {
+		played := false
+		for _, m := range g.LegalMoves() {
+			g.Position().MakeMove(m)
+			next := placement()
+			g.Position().UnmakeMove()
+			if !seen[next] {
+				seen[next] = true
+				if err := g.Apply(m); err != nil {
+					t.Fatal(err)
+				}
+				played = true
+				break
+			}
+		}
+		if !played {
+			break
+		}
+	}
+
+	pgn := roundTrip(t, g)
+	_, movetext, _ := strings.Cut(pgn, "\n\n")
+	lines := strings.Split(strings.TrimSuffix(movetext, "\n"), "\n")
+	if len(lines) < 3 {
+		t.Errorf("%d plies of movetext on %d lines", len(g.Moves()), len(lines))
+	}
+	for _, line := range lines {
+		if len(line) >= 80 || strings.HasPrefix(line, " ") || strings.HasSuffix(line, " ") {
+			t.Errorf("badly wrapped movetext line %q (%d characters)", line, len(line))
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package chess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PGNError is an error found while reading a PGN database. Game counts the
+// games in the order they appear from 1, Line is the line of input and Ply
+// the halfmove of the game, also from 1, the error was found at. Ply is 0 for
+// errors in the tag pair section.
+type PGNError struct {
+	Game int
+	Line int
+	Ply  int
+	Err  error
+}
+
+func (e *PGNError) Error() string {
+	return fmt.Sprintf("chess: pgn: game %d, line %d, ply %d: %v", e.Game, e.Line, e.Ply, e.Err)
+}
+
+func (e *PGNError) Unwrap() error {
+	return e.Err
+}
+
+// ParsePGN reads every game in a PGN database held in a string.
+func ParsePGN(pgn string) ([]*Game, error) {
+	return ReadPGN(strings.NewReader(pgn))
+}
+
+// ReadPGN reads every game in a PGN database. Each move is checked against
+// the legal moves of its position, including the moves of recursive
+// annotation variations, although only the main line is kept. Moves must be
+// SAN with uppercase piece letters, so bxc3 is always a pawn capture, rather
+// than the sloppy forms ParseSAN accepts. Comments, NAGs, e.p. marks and move
+// numbers are skipped. A game ends at its result token, at the tag pairs of
+// the next game, or at the end of input, and a result token must agree with
+// any result the final position decides, such as checkmate.
+//
+// The games read before an error are returned along with it.
+func ReadPGN(r io.Reader) ([]*Game, error) {
+	s := &pgnScanner{r: bufio.NewReader(r), line: 1, lineStart: true}
+	var games []*Game
+	for {
+		g, err := s.readGame(len(games) + 1)
+		if err != nil {
+			return games, err
+		}
+		if g == nil {
+			return games, nil
+		}
+		games = append(games, g)
+	}
+}
+
+type pgnToken struct {
+	text string
+	line int
+}
+
+type pgnScanner struct {
+	r         *bufio.Reader
+	line      int
+	lineStart bool
+	pending   *pgnToken
+}
+
+func (s *pgnScanner) readByte() (byte, error) {
+	c, err := s.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	s.lineStart = c == '\n'
+	if c == '\n' {
+		s.line++
+	}
+	return c, nil
+}
+
+// next returns the next token: a tag pair in brackets, a parenthesis, a NAG,
+// a result or a move. Comments, escaped lines and move numbers are skipped.
+// The token is empty at the end of input.
+func (s *pgnScanner) next() (pgnToken, error) {
+	if s.pending != nil {
+		token := *s.pending
+		s.pending = nil
+		return token, nil
+	}
+
+	for {
+		lineStart := s.lineStart
+		c, err := s.readByte()
+		if err == io.EOF {
+			return pgnToken{}, nil
+		} else if err != nil {
+			return pgnToken{}, err
+		}
+		line := s.line
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+		case c == ';' || (c == '%' && lineStart):
+			// Rest of line comment, or an escaped line
+			for c != '\n' && err == nil {
+				c, err = s.readByte()
+			}
+		case c == '{':
+			for c != '}' {
+				if c, err = s.readByte(); err != nil {
+					return pgnToken{}, &PGNError{Line: line, Err: errors.New("unterminated comment")}
+				}
+			}
+		case c == '[':
+			// A ] inside the quoted value doesn't end the tag pair
+			text := []byte{c}
+			quoted, escaped := false, false
+			for quoted || c != ']' {
+				if c, err = s.readByte(); err != nil {
+					return pgnToken{}, &PGNError{Line: line, Err: errors.New("unterminated tag pair")}
+				}
+				text = append(text, c)
+				switch {
+				case escaped:
+					escaped = false
+				case quoted && c == '\\':
+					escaped = true
+				case c == '"':
+					quoted = !quoted
+				}
+			}
+			return pgnToken{string(text), line}, nil
+		case c == '(' || c == ')':
+			return pgnToken{string(c), line}, nil
+		default:
+			text := []byte{c}
+			for {
+				c, err = s.r.ReadByte()
+				if err != nil {
+					break
+				}
+				if strings.IndexByte(" \t\r\n{};()[", c) >= 0 {
+					s.r.UnreadByte()
+					break
+				}
+				text = append(text, c)
+			}
+			s.lineStart = false
+			if token := stripMoveNumber(string(text)); token != "" {
+				return pgnToken{token, line}, nil
+			}
+		}
+	}
+}
+
+// stripMoveNumber removes a leading move number such as 12. or 12... from a
+// token, returning what is left.
+func stripMoveNumber(token string) string {
+	i := 0
+	for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(token) || token[i] != '.' {
+		return token
+	}
+	return strings.TrimLeft(token[i:], ".")
+}
+
+func isResultToken(token string) bool {
+	return token == "1-0" || token == "0-1" || token == "1/2-1/2" || token == "*"
+}
+
+// parseTagPair parses a tag pair such as [Event "Casual game"].
+func parseTagPair(text string) (Tag, error) {
+	inner := strings.TrimSpace(text[1 : len(text)-1])
+	name, value, ok := strings.Cut(inner, " ")
+	value = strings.TrimSpace(value)
+	if !ok || name == "" || len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return Tag{}, fmt.Errorf("malformed tag pair %s", text)
+	}
+	value = value[1 : len(value)-1]
+	value = strings.ReplaceAll(value, `\"`, `"`)
+	value = strings.ReplaceAll(value, `\\`, `\`)
+	return Tag{name, value}, nil
+}
+
+// pgnVariation is a line of moves being read: the main line, or a variation
+// that took back the move it replaced.
+type pgnVariation struct {
+	replaced Move
+	made     int
+}
+
+// readGame reads the next game, or returns nil at the end of input.
+func (s *pgnScanner) readGame(number int) (*Game, error) {
+	fail := func(line, ply int, err error) (*Game, error) {
+		var pgnErr *PGNError
+		if errors.As(err, &pgnErr) {
+			pgnErr.Game, pgnErr.Ply = number, ply
+			return nil, pgnErr
+		}
+		return nil, &PGNError{Game: number, Line: line, Ply: ply, Err: err}
+	}
+
+	// Tag pair section
+	var tags []Tag
+	token, err := s.next()
+	for ; err == nil && strings.HasPrefix(token.text, "["); token, err = s.next() {
+		tag, tagErr := parseTagPair(token.text)
+		if tagErr != nil {
+			return fail(token.line, 0, tagErr)
+		}
+		tags = append(tags, tag)
+	}
+	if err != nil {
+		return fail(s.line, 0, err)
+	}
+	if token.text == "" && len(tags) == 0 {
+		return nil, nil
+	}
+
+	startFEN := StartingFEN
+	for _, tag := range tags {
+		if tag.Name == "FEN" {
+			startFEN = tag.Value
+		}
+	}
+	position, err := ParseFEN(startFEN)
+	if err != nil {
+		return fail(token.line, 0, err)
+	}
+
+	// Movetext section. Variations are played on the same position: opening
+	// one takes back the move it replaces, and closing it takes back its own
+	// moves and replays the replaced one.
+	var mainLine []Move
+	lines := []pgnVariation{{}}
+	result, resultToken := "", token
+	for result == "" {
+		line := &lines[len(lines)-1]
+		ply := len(position.undo) + 1
+
+		switch {
+		case token.text == "":
+			// End of input
+			result = "*"
+		case strings.HasPrefix(token.text, "["):
+			// The next game started without a result
+			pending := token
+			s.pending = &pending
+			result = "*"
+		case isResultToken(token.text):
+			result, resultToken = token.text, token
+		case token.text == "(":
+			if line.made == 0 {
+				return fail(token.line, ply, errors.New("variation has no move to replace"))
+			}
+			replaced := position.undo[len(position.undo)-1].move
+			position.UnmakeMove()
+			line.made--
+			lines = append(lines, pgnVariation{replaced: replaced})
+		case token.text == ")":
+			if len(lines) == 1 {
+				return fail(token.line, ply, errors.New("unmatched )"))
+			}
+			for ; line.made > 0; line.made-- {
+				position.UnmakeMove()
+			}
+			position.MakeMove(line.replaced)
+			lines = lines[:len(lines)-1]
+			lines[len(lines)-1].made++
+		case strings.HasPrefix(token.text, "$"):
+			// Numeric annotation glyph
+		case token.text == "e.p.":
+			// En passant mark written apart from its capture
+		default:
+			m, moveErr := position.parseSAN(token.text, true)
+			if moveErr != nil {
+				return fail(token.line, ply, moveErr)
+			}
+			position.MakeMove(m)
+			line.made++
+			if len(lines) == 1 {
+				mainLine = append(mainLine, m)
+			}
+		}
+
+		if result != "" && len(lines) > 1 {
+			return fail(token.line, ply, errors.New("unterminated variation"))
+		}
+		if result == "" {
+			if token, err = s.next(); err != nil {
+				return fail(s.line, len(position.undo)+1, err)
+			}
+		}
+	}
+
+	// The moves were checked as they were read. Play them on a new game even
+	// past a draw the game has to stop at, such as fivefold repetition, as
+	// recorded games sometimes go on.
+	start, _ := ParseFEN(startFEN)
+	g := NewGameFromPosition(start)
+	for _, tag := range tags {
+		g.SetTag(tag.Name, tag.Value)
+	}
+	for _, m := range mainLine {
+		g.play(m)
+	}
+	if err := g.setRecordedResult(result); err != nil {
+		return fail(resultToken.line, len(mainLine)+1, err)
+	}
+	return g, nil
+}
@@ -0,0 +1,126 @@
+package chess
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testDatabase = `[Event "First"]
+[Site "Here"]
+[White "A \"quoted\" name"]
+
+% An escaped line, ignored
+1. e4 {A comment
+over two lines} e5 $1 2. Nf3 ; the rest of the line
+Nc6 (2... d6 3. d4 (3. Bc4 Be7) exd4) (2... Nf6) 3. Bb5 a6 1-0
+
+[Event "Second"]
+
+1. d3 Nc6 2. Bd2 Nb4 3. h3 N4d5 4. h4 Nc3 5. bxc3 *
+
+[Event "Third"]
+
+1. d4 d5
+[Event "Fourth"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 b - - 0 1"]
+
+1... Kd7 2. e4 1/2-1/2
+
+[Event "a]b"]
+
+1. e4 a6 2. e5 f5 3. exf6 e.p. *
+
+[Event "Sixth"]
+
+1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 4. Ng1 Ng8 5. Nf3 Nf6 6. Ng1 Ng8
+7. Nf3 Nf6 8. Ng1 Ng8 9. e4 1/2-1/2
+`
+
+func TestReadPGN(t *testing.T) {
+	games, err := ParsePGN(testDatabase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 6 {
+		t.Fatalf("got %d games, want 6", len(games))
+	}
+
+	for i, want := range []struct {
+		event  string
+		moves  []string
+		result Result
+	}{
+		{"First", []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6"}, Result{Winner: White, Reason: Resignation}},
+		{"Second", []string{"d3", "Nc6", "Bd2", "Nb4", "h3", "Nd5", "h4", "Nc3", "bxc3"}, Result{}},
+		// Cut short by the tag pairs of the next game
+		{"Third", []string{"d4", "d5"}, Result{}},
+		{"Fourth", []string{"Kd7", "e4"}, Result{Reason: Agreement}},
+		{"a]b", []string{"e4", "a6", "e5", "f5", "exf6"}, Result{}},
+		// Played on past fivefold repetition
+		{"Sixth", []string{
+			"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8",
+			"Nf3", "Nf6", "Ng1", "Ng8", "Nf3", "Nf6", "Ng1", "Ng8", "e4",
+		}, Result{Reason: Agreement}},
+	} {
+		g := games[i]
+		if g.tag("Event") != want.event {
+			t.Errorf("game %d: got Event %q, want %q", i+1, g.tag("Event"), want.event)
+		}
+		if !reflect.DeepEqual(g.MovesSAN(), want.moves) {
+			t.Errorf("game %d: got moves %v, want %v", i+1, g.MovesSAN(), want.moves)
+		}
+		if g.Outcome() != want.result {
+			t.Errorf("game %d: got %v, want %v", i+1, g.Outcome(), want.result)
+		}
+	}
+
+	if g := games[0]; g.tag("Site") != "Here" || g.tag("White") != `A "quoted" name` {
+		t.Errorf("got Site %q and White %q", g.tag("Site"), g.tag("White"))
+	}
+	if fen := games[3].Position().FEN(); fen != "8/3k4/8/8/4P3/8/8/4K3 b - e3 0 2" {
+		t.Errorf("game from FEN ended at %s", fen)
+	}
+}
+
+func TestReadPGNErrors(t *testing.T) {
+	for _, want := range []struct {
+		pgn              string
+		game, line, ply  int
+		illegal          bool
+		gamesBeforeError int
+	}{
+		{"[Event \"x\"]\n\n1. e4 e5 2. Ke3 *", 1, 3, 3, true, 0},
+		// A bishop could take on c3, but the move is written as a pawn's
+		{"1. e4 *\n\n[FEN \"4k3/8/8/8/8/2n5/3B4/4K3 w - - 0 1\"]\n\n1. bxc3 *", 2, 5, 1, true, 1},
+		{"1. e4 e5\n(1... e6 2. Nc3 (2. d4 Qxd4) d5) 2. Nf3 *", 1, 2, 4, true, 0},
+		{"[Event Casual]\n\n1. e4 *", 1, 1, 0, false, 0},
+		{"[Event \"x\"\n", 1, 1, 0, false, 0},
+		{"[Event \"x]\n\n1. e4 *", 1, 1, 0, false, 0},
+		{"[FEN \"8/8/8/8/8/8/8/8 w - - 0 1\"]\n\n*", 1, 3, 0, false, 0},
+		{"1. e4 e5\n2. Nf3 {never closed\n", 1, 2, 4, false, 0},
+		{"1. e4 ) *", 1, 1, 2, false, 0},
+		{"( 1. e4 ) *", 1, 1, 1, false, 0},
+		{"1. e4\n(1. d4 *", 1, 2, 2, false, 0},
+		// The result contradicts the checkmate on the board
+		{"1. e4 e5 2. Bc4 Nc6 3. Qh5 Nf6\n4. Qxf7# 0-1", 1, 2, 8, false, 0},
+	} {
+		games, err := ParsePGN(want.pgn)
+		var pgnErr *PGNError
+		if !errors.As(err, &pgnErr) {
+			t.Errorf("%q: got error %v, want a PGNError", want.pgn, err)
+			continue
+		}
+		if pgnErr.Game != want.game || pgnErr.Line != want.line || pgnErr.Ply != want.ply {
+			t.Errorf("%q: got game %d, line %d, ply %d, want game %d, line %d, ply %d",
+				want.pgn, pgnErr.Game, pgnErr.Line, pgnErr.Ply, want.game, want.line, want.ply)
+		}
+		if errors.Is(err, ErrIllegalMove) != want.illegal {
+			t.Errorf("%q: got %v", want.pgn, err)
+		}
+		if len(games) != want.gamesBeforeError {
+			t.Errorf("%q: got %d games before the error, want %d", want.pgn, len(games), want.gamesBeforeError)
+		}
+	}
+}
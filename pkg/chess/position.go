@@ -0,0 +1,179 @@
+package chess
+
+// Position is the full state of a game at one moment: the pieces on the
+// board, the side to move, castling rights, the en passant target, the number
+// of halfmoves since the last pawn move or capture and the fullmove number.
+//
+// Moves are made and unmade in place. A Position is not safe for concurrent
+// use, even by methods that only look at it, since move generation makes and
+// unmakes moves to test them.
+type Position struct {
+	board         [64]Piece
+	pieces        [3][7]Bitboard
+	colors        [3]Bitboard
+	turn          Color
+	castling      CastlingRights
+	enPassant     Square
+	halfmoveClock int
+	fullmove      int
+	hash          uint64
+	undo          []undo
+}
+
+// StartingPosition returns the position at the start of a game.
+func StartingPosition() *Position {
+	p, err := ParseFEN(StartingFEN)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Clone returns a copy of the position, including the moves that can be
+// unmade, that can be changed without affecting p.
+func (p *Position) Clone() *Position {
+	c := *p
+	c.undo = append(make([]undo, 0, len(p.undo)+64), p.undo...)
+	return &c
+}
+
+// Turn returns the side to move.
+func (p *Position) Turn() Color {
+	return p.turn
+}
+
+// PieceAt returns the piece on sq, or NoPiece if it is empty or off the board.
+func (p *Position) PieceAt(sq Square) Piece {
+	if !sq.IsValid() {
+		return NoPiece
+	}
+	return p.board[sq]
+}
+
+// Pieces returns the squares holding pieces of color c and type t.
+func (p *Position) Pieces(c Color, t PieceType) Bitboard {
+	return p.pieces[c][t]
+}
+
+// Occupancy returns the squares holding pieces of color c, or every occupied
+// square for NoColor.
+func (p *Position) Occupancy(c Color) Bitboard {
+	if c == NoColor {
+		return p.colors[White] | p.colors[Black]
+	}
+	return p.colors[c]
+}
+
+// CastlingRights returns the castling moves each side may still make.
+func (p *Position) CastlingRights() CastlingRights {
+	return p.castling
+}
+
+// EnPassant returns the square a pawn skipped with a two square advance on
+// the previous move, or NoSquare.
+func (p *Position) EnPassant() Square {
+	return p.enPassant
+}
+
+// HalfmoveClock returns the number of halfmoves since the last pawn move or
+// capture.
+func (p *Position) HalfmoveClock() int {
+	return p.halfmoveClock
+}
+
+// FullmoveNumber returns the number of the current move, starting at 1 and
+// increasing after each of Black's moves.
+func (p *Position) FullmoveNumber() int {
+	return p.fullmove
+}
+
+// Hash returns the Zobrist hash of the position. It is the key Polyglot
+// opening books use, so it can look positions up in them as well as identify
+// positions in tables and game databases.
+func (p *Position) Hash() uint64 {
+	return p.hash
+}
+
+// InCheck reports whether the side to move is in check.
+func (p *Position) InCheck() bool {
+	return p.checkers() != 0
+}
+
+// Checkers returns the squares of every piece giving check to the side to move.
+func (p *Position) Checkers() []Square {
+	return p.checkers().Squares()
+}
+
+func (p *Position) checkers() Bitboard {
+	king, ok := p.kingSquare(p.turn)
+	if !ok {
+		return 0
+	}
+	return p.attackers(king, p.turn.Other(), p.Occupancy(NoColor))
+}
+
+func (p *Position) kingSquare(c Color) (Square, bool) {
+	king := p.pieces[c][King]
+	return king.First(), king != 0
+}
+
+func (p *Position) isAttacked(sq Square, by Color) bool {
+	return p.attackers(sq, by, p.Occupancy(NoColor)) != 0
+}
+
+// attackers returns the squares of every piece of color by attacking sq, with
+// sliders blocked by the pieces in occupied.
+func (p *Position) attackers(sq Square, by Color, occupied Bitboard) Bitboard {
+	pieces := &p.pieces[by]
+	return pawnAttacks[by.Other()][sq]&pieces[Pawn] |
+		knightAttacks[sq]&pieces[Knight] |
+		kingAttacks[sq]&pieces[King] |
+		RookAttacks(sq, occupied)&(pieces[Rook]|pieces[Queen]) |
+		BishopAttacks(sq, occupied)&(pieces[Bishop]|pieces[Queen])
+}
+
+// key identifies the position for repetition. Two positions are the same when
+// the same pieces stand on the same squares, the same side is to move and the
+// same castling and en passant captures are possible. That is the hash, less
+// the en passant key when the capture it stands for isn't legal.
+func (p *Position) key() uint64 {
+	key := p.hash
+	if enPassant := p.enPassantKey(); enPassant != 0 {
+		for _, m := range p.LegalMoves() {
+			if m.Has(EnPassant) {
+				return key
+			}
+		}
+		key ^= enPassant
+	}
+	return key
+}
+
+// IsRepetition reports whether the position occurred before among those
+// MakeMove has passed through since the last pawn move or capture.
+func (p *Position) IsRepetition() bool {
+	for i := len(p.undo) - 4; i >= 0 && i >= len(p.undo)-p.halfmoveClock; i -= 2 {
+		if p.undo[i].hash == p.hash {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInsufficientMaterial reports whether neither side can possibly mate:
+// king against king, king and minor piece against king, or kings and bishops
+// that all stand on the same colored squares.
+func (p *Position) hasInsufficientMaterial() bool {
+	for _, c := range [2]Color{White, Black} {
+		if p.pieces[c][Pawn]|p.pieces[c][Rook]|p.pieces[c][Queen] != 0 {
+			return false
+		}
+	}
+	knights := p.pieces[White][Knight] | p.pieces[Black][Knight]
+	bishops := p.pieces[White][Bishop] | p.pieces[Black][Bishop]
+
+	if (knights | bishops).Count() <= 1 {
+		return true
+	}
+	return knights == 0 && (bishops&LightSquares == 0 || bishops&^LightSquares == 0)
+}
@@ -0,0 +1,92 @@
+package chess
+
+// Reason is why a game ended.
+type Reason int
+
+const (
+	Ongoing Reason = iota
+	Checkmate
+	Stalemate
+	Resignation
+	Timeout
+	DrawByRule
+	Agreement
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Ongoing:
+		return "ongoing"
+	case Checkmate:
+		return "checkmate"
+	case Stalemate:
+		return "stalemate"
+	case Resignation:
+		return "resignation"
+	case Timeout:
+		return "timeout"
+	case DrawByRule:
+		return "draw rule"
+	case Agreement:
+		return "agreement"
+	}
+	return ""
+}
+
+// DrawRule is the rule a game was drawn under when its Reason is DrawByRule.
+type DrawRule int
+
+const (
+	NoDrawRule DrawRule = iota
+	FiftyMoveRule
+	SeventyFiveMoveRule
+	ThreefoldRepetition
+	FivefoldRepetition
+	InsufficientMaterial
+)
+
+func (dr DrawRule) String() string {
+	switch dr {
+	case FiftyMoveRule:
+		return "fifty-move rule"
+	case SeventyFiveMoveRule:
+		return "seventy-five-move rule"
+	case ThreefoldRepetition:
+		return "threefold repetition"
+	case FivefoldRepetition:
+		return "fivefold repetition"
+	case InsufficientMaterial:
+		return "insufficient material"
+	}
+	return ""
+}
+
+// Result is the outcome of a game. Winner is NoColor for draws and for games
+// that are still ongoing.
+type Result struct {
+	Winner Color
+	Reason Reason
+	Rule   DrawRule
+}
+
+// IsOver reports whether the game has ended.
+func (r Result) IsOver() bool {
+	return r.Reason != Ongoing
+}
+
+// IsDraw reports whether the game ended without a winner.
+func (r Result) IsDraw() bool {
+	return r.IsOver() && r.Winner == NoColor
+}
+
+func (r Result) String() string {
+	switch {
+	case !r.IsOver():
+		return "Game in progress"
+	case r.Reason == DrawByRule:
+		return "Draw by " + r.Rule.String()
+	case r.IsDraw():
+		return "Draw by " + r.Reason.String()
+	}
+	return r.Winner.String() + " wins by " + r.Reason.String()
+}
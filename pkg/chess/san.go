@@ -0,0 +1,189 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var sanPieceLetters = [7]string{"", "", "R", "N", "B", "Q", "K"}
+
+// SAN returns m in Standard Algebraic Notation, such as Nf3, exd5, e8=Q, O-O
+// or Qh4#. The move must be legal in the position.
+func (p *Position) SAN(m Move) string {
+	var san string
+	switch {
+	case m.Has(CastleKingSide):
+		san = "O-O"
+	case m.Has(CastleQueenSide):
+		san = "O-O-O"
+	case m.Piece.Type == Pawn:
+		if m.IsCapture() {
+			san = m.From.String()[:1] + "x"
+		}
+		san += m.To.String()
+		if m.IsPromotion() {
+			san += "=" + sanPieceLetters[m.Promotion]
+		}
+	default:
+		san = sanPieceLetters[m.Piece.Type] + p.sanDisambiguation(m)
+		if m.IsCapture() {
+			san += "x"
+		}
+		san += m.To.String()
+	}
+
+	p.MakeMove(m)
+	if p.InCheck() {
+		if len(p.LegalMoves()) == 0 {
+			san += "#"
+		} else {
+			san += "+"
+		}
+	}
+	p.UnmakeMove()
+	return san
+}
+
+// sanDisambiguation returns what SAN needs to tell m apart from moves of
+// other pieces of the same type to the same square: the from file if that is
+// enough, otherwise the from rank, otherwise both.
+func (p *Position) sanDisambiguation(m Move) string {
+	ambiguous, sameFile, sameRank := false, false, false
+	for _, other := range p.LegalMoves() {
+		if other.Piece != m.Piece || other.To != m.To || other.From == m.From {
+			continue
+		}
+		ambiguous = true
+		if other.From.File() == m.From.File() {
+			sameFile = true
+		}
+		if other.From.Rank() == m.From.Rank() {
+			sameRank = true
+		}
+	}
+
+	from := m.From.String()
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return from[:1]
+	case !sameRank:
+		return strconv.Itoa(m.From.Rank() + 1)
+	}
+	return from
+}
+
+// ParseSAN returns the legal move written in Standard Algebraic Notation.
+// It accepts the sloppy forms people and other programs often write as well:
+// lowercase piece letters (nf3), the from square spelled out (Ng1f3, ng1-f3,
+// e2e4, b1c3), missing or extra capture marks (exd5, ed5, Nf3 for Nxf3),
+// promotion without = (e8Q, e8q), castling with zeros (0-0) and trailing
+// check, mate, annotation and e.p. marks. A lowercase b is read as the b-file
+// unless no pawn move or move from a b-file square fits, and only then as a
+// bishop. It fails if no legal move or more than one legal move fits.
+func (p *Position) ParseSAN(san string) (Move, error) {
+	return p.parseSAN(san, false)
+}
+
+// parseSAN reads SAN as ParseSAN does. When strict, as in PGN movetext, piece
+// letters must be uppercase and moves can't be written in long algebraic
+// notation, so a lowercase b is always a file.
+func (p *Position) parseSAN(san string, strict bool) (Move, error) {
+	text := strings.TrimRight(strings.TrimSpace(san), "+#!?")
+	text = strings.TrimSuffix(strings.TrimSuffix(text, "e.p."), "ep")
+	text = strings.NewReplacer("x", "", ":", "", "-", "", "=", "").Replace(text)
+	if text == "" {
+		return Move{}, fmt.Errorf("%w: %q", ErrIllegalMove, san)
+	}
+
+	switch strings.ToUpper(strings.ReplaceAll(text, "0", "O")) {
+	case "OO", "OOO":
+		flag := CastleKingSide
+		if len(text) == 3 {
+			flag = CastleQueenSide
+		}
+		var matches []Move
+		for _, m := range p.LegalMoves() {
+			if m.Has(flag) {
+				matches = append(matches, m)
+			}
+		}
+		return sanMatch(san, matches)
+	}
+
+	var matches []Move
+	switch c := text[0]; {
+	case strings.IndexByte("RNBQK", c) >= 0:
+		matches = p.sanMoves(Rook+PieceType(strings.IndexByte("RNBQK", c)), text[1:])
+	case !strict && strings.IndexByte("rnqk", c) >= 0:
+		matches = p.sanMoves(Rook+PieceType(strings.IndexByte("rnbqk", c)), text[1:])
+	default:
+		matches = p.sanMoves(Pawn, text)
+		if len(matches) == 0 && !strict && len(text) >= 4 && c >= 'a' && c <= 'h' {
+			// Long algebraic notation without a piece letter, such as g1f3
+			for _, t := range [5]PieceType{Rook, Knight, Bishop, Queen, King} {
+				matches = append(matches, p.sanMoves(t, text)...)
+			}
+		}
+		if len(matches) == 0 && !strict && c == 'b' {
+			matches = p.sanMoves(Bishop, text[1:])
+		}
+	}
+	return sanMatch(san, matches)
+}
+
+// sanMoves returns the legal moves of pieceType fitting the rest of a SAN
+// move once its piece letter, capture and promotion marks are removed: an
+// optional from file, rank or square, the to square and an optional promotion
+// piece letter.
+func (p *Position) sanMoves(pieceType PieceType, text string) []Move {
+	promotion := NoPieceType
+	if n := len(text); n >= 3 && strings.IndexByte("rnbqRNBQ", text[n-1]) >= 0 {
+		promotion = Rook + PieceType(strings.IndexByte("RNBQ", strings.ToUpper(text[n-1:])[0]))
+		text = text[:n-1]
+	}
+	if len(text) < 2 {
+		return nil
+	}
+	to, err := ParseSquare(text[len(text)-2:])
+	if err != nil {
+		return nil
+	}
+
+	// Whatever is left tells apart pieces that could reach the same square
+	fromFile, fromRank := -1, -1
+	for _, c := range text[:len(text)-2] {
+		switch {
+		case c >= 'a' && c <= 'h' && fromFile < 0 && fromRank < 0:
+			fromFile = int(c - 'a')
+		case c >= '1' && c <= '8' && fromRank < 0:
+			fromRank = int(c - '1')
+		default:
+			return nil
+		}
+	}
+
+	var matches []Move
+	for _, m := range p.LegalMoves() {
+		if m.Piece.Type != pieceType || m.To != to || m.Promotion != promotion {
+			continue
+		}
+		if (fromFile >= 0 && m.From.File() != fromFile) || (fromRank >= 0 && m.From.Rank() != fromRank) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+func sanMatch(san string, matches []Move) (Move, error) {
+	switch len(matches) {
+	case 0:
+		return Move{}, fmt.Errorf("%w: %q", ErrIllegalMove, san)
+	case 1:
+		return matches[0], nil
+	}
+	return Move{}, fmt.Errorf("%w: %q is ambiguous", ErrIllegalMove, san)
+}
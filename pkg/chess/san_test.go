@@ -0,0 +1,139 @@
+package chess
+
+import (
+	"errors"
+	"testing"
+)
+
+// afterDPawnBishop is the position after 1. d4 d5 2. Bd2 Nf6, where the
+// bishop on d2 reaches b4 as the b-pawn does.
+const afterDPawnBishop = "rnbqkb1r/ppp1pppp/5n2/3p4/3P4/8/PPPBPPPP/RN1QKBNR w KQkq - 2 3"
+
+func TestParseSAN(t *testing.T) {
+	for _, want := range []struct {
+		fen, san, move string
+	}{
+		// Strict SAN and the sloppy forms of it
+		{StartingFEN, "Nf3", "g1f3"},
+		{StartingFEN, "nf3", "g1f3"},
+		{StartingFEN, "ng1f3", "g1f3"},
+		{StartingFEN, "Ng1-f3", "g1f3"},
+		{StartingFEN, "e4", "e2e4"},
+		{StartingFEN, "e2e4", "e2e4"},
+		{StartingFEN, "e2-e4", "e2e4"},
+		{StartingFEN, "g1f3", "g1f3"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "exd5", "e4d5"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "ed5", "e4d5"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "e4xd5!?", "e4d5"},
+		{"4k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a8=Q", "a7a8q"},
+		{"4k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a8Q+", "a7a8q"},
+		{"4k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a8q", "a7a8q"},
+		{"4k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a8=N", "a7a8n"},
+		{"r3k3/8/8/8/8/8/8/4K2R w Kq - 0 1", "O-O", "e1g1"},
+		{"r3k3/8/8/8/8/8/8/4K2R w Kq - 0 1", "0-0", "e1g1"},
+		{"r3k3/8/8/8/8/8/8/4K2R b Kq - 0 1", "O-O-O", "e8c8"},
+		{"r3k3/8/8/8/8/8/8/4K2R b Kq - 0 1", "0-0-0", "e8c8"},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "exd6e.p.", "e5d6"},
+		{"4k3/8/8/8/8/5p2/8/4K1N1 w - - 0 1", "Nf3", "g1f3"},
+
+		// A lowercase b is a file unless no pawn move or move from the b-file fits
+		{StartingFEN, "b1c3", "b1c3"},
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", "b8c6", "b8c6"},
+		{"4k3/8/8/8/8/2n5/1P1B4/4K3 w - - 0 1", "bxc3", "b2c3"},
+		{"4k3/8/8/8/8/2n5/1P1B4/4K3 w - - 0 1", "bc3", "b2c3"},
+		{"4k3/8/8/8/8/2n5/1P1B4/4K3 w - - 0 1", "b2c3", "b2c3"},
+		{"4k3/8/8/8/8/2n5/1P1B4/4K3 w - - 0 1", "b2b4", "b2b4"},
+		{"4k3/8/8/8/8/2n5/1P1B4/4K3 w - - 0 1", "Bxc3", "d2c3"},
+		{"4k3/8/8/8/8/2n5/3B4/4K3 w - - 0 1", "bxc3", "d2c3"},
+		{afterDPawnBishop, "b2b4", "b2b4"},
+		{afterDPawnBishop, "b4", "b2b4"},
+		{afterDPawnBishop, "bb4", "b2b4"},
+		{afterDPawnBishop, "Bb4", "d2b4"},
+	} {
+		p, err := ParseFEN(want.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := p.ParseSAN(want.san)
+		if err != nil {
+			t.Errorf("%s %s: %v", want.fen, want.san, err)
+		} else if m.String() != want.move {
+			t.Errorf("%s %s: got %v, want %s", want.fen, want.san, m, want.move)
+		}
+	}
+}
+
+func TestParseSANErrors(t *testing.T) {
+	for _, want := range []struct {
+		fen, san string
+		strict   bool
+	}{
+		{StartingFEN, "e5", false},
+		{StartingFEN, "", false},
+		{StartingFEN, "Nd2", false},
+		{StartingFEN, "O-O", false},
+		{StartingFEN, "e2e5", false},
+		// Knights on b1 and f3 both reach d2
+		{"4k3/8/8/8/8/5N2/8/1N2K3 w - - 0 1", "Nd2", false},
+		// Strict SAN has no lowercase piece letters or long algebraic moves
+		{StartingFEN, "nf3", true},
+		{StartingFEN, "g1f3", true},
+		{"4k3/8/8/8/8/2n5/3B4/4K3 w - - 0 1", "bxc3", true},
+	} {
+		p, err := ParseFEN(want.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m, err := p.parseSAN(want.san, want.strict); !errors.Is(err, ErrIllegalMove) {
+			t.Errorf("%s %q: got %v, %v, want an illegal move error", want.fen, want.san, m, err)
+		}
+	}
+}
+
+func TestSAN(t *testing.T) {
+	for _, want := range []struct {
+		fen, move, san string
+	}{
+		{StartingFEN, "g1f3", "Nf3"},
+		{StartingFEN, "e2e4", "e4"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2", "e4d5", "exd5"},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", "exd6"},
+		{"1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7b8q", "axb8=Q+"},
+		{"1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a7a8n", "a8=N"},
+		{"r3k3/8/8/8/8/8/8/4K2R w Kq - 0 1", "e1g1", "O-O"},
+		{"r3k3/8/8/8/8/8/8/4K2R b Kq - 0 1", "e8c8", "O-O-O"},
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "a1a8", "Ra8#"},
+
+		// Disambiguation by file, then rank, then square
+		{"4k3/8/8/8/8/5N2/8/1N2K3 w - - 0 1", "b1d2", "Nbd2"},
+		{"4k3/8/8/8/8/5N2/8/1N2K3 w - - 0 1", "f3d2", "Nfd2"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a1a3", "R1a3"},
+		{"4k3/8/8/R7/8/8/8/R3K3 w - - 0 1", "a5a3", "R5a3"},
+		{"7K/8/8/7k/8/Q7/8/Q1Q5 w - - 0 1", "a1b2", "Qa1b2"},
+		{"7K/8/8/7k/8/Q7/8/Q1Q5 w - - 0 1", "a3b2", "Q3b2"},
+		{"7K/8/8/7k/8/Q7/8/Q1Q5 w - - 0 1", "c1b2", "Qcb2"},
+		// A pinned knight doesn't make the other one ambiguous
+		{"k7/8/8/4N3/8/8/1N6/4K3 w - - 0 1", "b2d3", "Nbd3"},
+		{"k3r3/8/8/4N3/8/8/1N6/4K3 w - - 0 1", "b2d3", "Nd3"},
+	} {
+		p, err := ParseFEN(want.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var move Move
+		for _, m := range p.LegalMoves() {
+			if m.String() == want.move {
+				move = m
+			}
+		}
+		if move.String() != want.move {
+			t.Fatalf("%s: %s is not legal", want.fen, want.move)
+		}
+		if san := p.SAN(move); san != want.san {
+			t.Errorf("%s %s: got %s, want %s", want.fen, want.move, san, want.san)
+		}
+		if m, err := p.ParseSAN(want.san); err != nil || m != move {
+			t.Errorf("%s %s: reading back gave %v, %v", want.fen, want.san, m, err)
+		}
+	}
+}
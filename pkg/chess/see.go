@@ -0,0 +1,71 @@
+package chess
+
+// seeValues are the piece values static exchange evaluation counts in, in
+// centipawns. The king is worth more than everything else together, so an
+// exchange never ends with it captured.
+var seeValues = [7]int{Pawn: 100, Knight: 300, Bishop: 300, Rook: 500, Queen: 900, King: 20000}
+
+// seeOrder is the order pieces join an exchange in, cheapest first.
+var seeOrder = [6]PieceType{Pawn, Knight, Bishop, Rook, Queen, King}
+
+// SEE returns the material the side to move gains by playing m and letting
+// both sides then take back on its destination square for as long as it pays,
+// always with their least valuable piece. It is in centipawns, counting a
+// pawn 100, a knight or bishop 300, a rook 500 and a queen 900.
+//
+// A negative result means the move loses material: the piece moved will be
+// taken for less than it is worth. Pins are not taken into account.
+func (p *Position) SEE(m Move) int {
+	to := m.To
+	occupied := p.Occupancy(NoColor) &^ m.From.Bitboard()
+	if m.Has(EnPassant) {
+		occupied &^= NewSquare(to.File(), m.From.Rank()).Bitboard()
+	}
+
+	var gain [32]int
+	gain[0] = seeValues[m.Captured.Type]
+	onSquare := seeValues[m.Piece.Type]
+	if m.IsPromotion() {
+		gain[0] += seeValues[m.Promotion] - seeValues[Pawn]
+		onSquare = seeValues[m.Promotion]
+	}
+
+	queens := p.pieces[White][Queen] | p.pieces[Black][Queen]
+	rooks := p.pieces[White][Rook] | p.pieces[Black][Rook] | queens
+	bishops := p.pieces[White][Bishop] | p.pieces[Black][Bishop] | queens
+	attackers := (p.attackers(to, White, occupied) | p.attackers(to, Black, occupied)) & occupied
+	side := m.Piece.Color.Other()
+	d := 0
+	for d < len(gain)-1 {
+		// What side gains by taking the piece on the square, if it can
+		d++
+		gain[d] = onSquare - gain[d-1]
+
+		from, piece := NoSquare, NoPieceType
+		for _, t := range seeOrder {
+			if b := attackers & p.pieces[side][t]; b != 0 {
+				from, piece = b.First(), t
+				break
+			}
+		}
+		if from == NoSquare {
+			break
+		}
+
+		// Sliders behind the piece join in as it leaves
+		occupied &^= from.Bitboard()
+		attackers |= RookAttacks(to, occupied)&rooks | BishopAttacks(to, occupied)&bishops
+		attackers &= occupied
+		onSquare = seeValues[piece]
+		side = side.Other()
+	}
+
+	// The last gain is for a capture no piece was left to make. Working back
+	// from it, each side takes only if that beats stopping.
+	for d--; d > 0; d-- {
+		if -gain[d] < gain[d-1] {
+			gain[d-1] = -gain[d]
+		}
+	}
+	return gain[0]
+}
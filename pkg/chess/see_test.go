@@ -0,0 +1,42 @@
+package chess
+
+import "testing"
+
+func TestSEE(t *testing.T) {
+	for _, want := range []struct {
+		fen  string
+		move string
+		see  int
+	}{
+		// Undefended pawn
+		{"1k1r4/1pp4p/p7/4p3/8/P5P1/1PP4P/2K1R3 w - - 0 1", "Rxe5", 100},
+		// Knight for a pawn, then the exchange stops
+		{"1k1r3q/1ppn3p/p4b2/4p3/8/P2N2P1/1PP1R1BP/2K1Q3 w - - 0 1", "Nxe5", -200},
+		// Queen for a pawn
+		{"4k3/8/3p4/4p3/8/8/8/4QK2 w - - 0 1", "Qxe5+", -800},
+		// The rook behind the first one keeps the exchange going
+		{"3rk3/8/8/3p4/8/3R4/3R4/3K4 w - - 0 1", "Rxd5", 100},
+		{"3rk3/8/8/3p4/8/8/3R4/3K4 w - - 0 1", "Rxd5", -400},
+		// A king can only take back an undefended piece
+		{"8/8/8/4k3/3n4/8/1B6/3RK3 w - - 0 1", "Rxd4", 300},
+		{"8/8/8/4k3/3n4/8/8/3RK3 w - - 0 1", "Rxd4", -200},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "exd6", 100},
+		// Promoting to a queen that is taken at once
+		{"1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "a8=Q", -100},
+		{"1r2k3/P7/8/8/8/8/8/4K3 w - - 0 1", "axb8=Q+", 1300},
+		// A quiet move to an attacked square
+		{"4k3/8/8/2p5/8/8/2N5/4K3 w - - 0 1", "Nb4", -300},
+	} {
+		p, err := ParseFEN(want.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := p.ParseSAN(want.move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if see := p.SEE(m); see != want.see {
+			t.Errorf("%s %s: got %d, want %d", want.fen, want.move, see, want.see)
+		}
+	}
+}
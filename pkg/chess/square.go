@@ -0,0 +1,76 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Square is a square on the board, numbered from a1 = 0, b1 = 1 through
+// h1 = 7, a2 = 8 and so on up to h8 = 63. Files count from 0 for the a-file
+// and ranks from 0 for White's home rank.
+type Square int8
+
+// The squares of the board.
+const (
+	A1, B1, C1, D1, E1, F1, G1, H1 Square = 8*iota + 0, 8*iota + 1, 8*iota + 2, 8*iota + 3, 8*iota + 4, 8*iota + 5, 8*iota + 6, 8*iota + 7
+	A2, B2, C2, D2, E2, F2, G2, H2
+	A3, B3, C3, D3, E3, F3, G3, H3
+	A4, B4, C4, D4, E4, F4, G4, H4
+	A5, B5, C5, D5, E5, F5, G5, H5
+	A6, B6, C6, D6, E6, F6, G6, H6
+	A7, B7, C7, D7, E7, F7, G7, H7
+	A8, B8, C8, D8, E8, F8, G8, H8
+)
+
+// NoSquare is used where a square is optional, such as the en passant target.
+const NoSquare Square = -1
+
+// NewSquare returns the square on file and rank, both counted from 0, or
+// NoSquare if that is off the board.
+func NewSquare(file, rank int) Square {
+	if file < 0 || file > 7 || rank < 0 || rank > 7 {
+		return NoSquare
+	}
+	return Square(rank*8 + file)
+}
+
+// ParseSquare parses a square in algebraic notation, such as e4.
+func ParseSquare(s string) (Square, error) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return NoSquare, fmt.Errorf("chess: invalid square %q", s)
+	}
+	return NewSquare(int(s[0]-'a'), int(s[1]-'1')), nil
+}
+
+// File returns the square's file, 0 for the a-file through 7 for the h-file.
+func (sq Square) File() int {
+	return int(sq) % 8
+}
+
+// Rank returns the square's rank, 0 for the first rank through 7 for the
+// eighth.
+func (sq Square) Rank() int {
+	return int(sq) / 8
+}
+
+// IsValid reports whether the square lies on the board.
+func (sq Square) IsValid() bool {
+	return sq >= A1 && sq <= H8
+}
+
+// String formats the square in algebraic notation, such as e4.
+func (sq Square) String() string {
+	if !sq.IsValid() {
+		return "-"
+	}
+	return string(rune('a'+sq.File())) + strconv.Itoa(sq.Rank()+1)
+}
+
+// offset returns the square rank ranks up and file files right of sq, or
+// NoSquare if that is off the board.
+func (sq Square) offset(rank, file int) Square {
+	if !sq.IsValid() {
+		return NoSquare
+	}
+	return NewSquare(sq.File()+file, sq.Rank()+rank)
+}
@@ -0,0 +1,39 @@
+package chess
+
+import "testing"
+
+// Reference keys from the Polyglot book format specification, each after the
+// moves listed from the starting position.
+var polyglotKeys = []struct {
+	moves []string
+	key   uint64
+}{
+	{nil, 0x463b96181691fc9c},
+	{[]string{"e4"}, 0x823c9b50fd114196},
+	{[]string{"e4", "d5"}, 0x0756b94461c50fb0},
+	{[]string{"e4", "d5", "e5"}, 0x662fafb965db29d4},
+	{[]string{"e4", "d5", "e5", "f5"}, 0x22a48b5a8e47ff78},
+	{[]string{"e4", "d5", "e5", "f5", "Ke2"}, 0x652a607ca3f242c1},
+	{[]string{"e4", "d5", "e5", "f5", "Ke2", "Kf7"}, 0x00fdd303c946bdd9},
+	{[]string{"a4", "b5", "h4", "b4", "c4"}, 0x3c8123ea7b067637},
+	{[]string{"a4", "b5", "h4", "b4", "c4", "bxc3", "Ra3"}, 0x5c3f9b829b279560},
+}
+
+func TestPolyglotKeys(t *testing.T) {
+	for _, want := range polyglotKeys {
+		p := StartingPosition()
+		for _, san := range want.moves {
+			m, err := p.ParseSAN(san)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.MakeMove(m)
+		}
+		if p.Hash() != want.key {
+			t.Errorf("after %v: got key %016x, want %016x", want.moves, p.Hash(), want.key)
+		}
+		if fresh, err := ParseFEN(p.FEN()); err != nil || fresh.Hash() != want.key {
+			t.Errorf("after %v: position parsed from FEN has a different key", want.moves)
+		}
+	}
+}
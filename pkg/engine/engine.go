@@ -0,0 +1,90 @@
+// Package engine plays chess: it searches the positions of package chess for
+// the best move within a depth or time limit.
+//
+// Like package chess it never reads input or prints output.
+package engine
+
+import (
+	"time"
+
+	"ryan/chess/pkg/chess"
+)
+
+// Scores are in centipawns from the point of view of the side to move. Mate
+// scores count down from Mate by the number of plies to the mate, so a mate
+// sooner scores higher.
+const (
+	Mate = 32000
+
+	// MaxPly is the deepest the search goes, counting extensions.
+	MaxPly = 128
+
+	// DefaultDepth is how deep a search with no limits set goes.
+	DefaultDepth = 5
+
+	infinity = Mate + 1
+)
+
+// Limits bounds a search. A zero field sets no limit; with no limits at all
+// the search stops at DefaultDepth.
+type Limits struct {
+	// Depth is the number of plies to search.
+	Depth int
+	// MoveTime is how long to search for. The search stops once it runs out,
+	// keeping the best move of the last depth it finished.
+	MoveTime time.Duration
+}
+
+// Result is the outcome of a search.
+type Result struct {
+	// Move is the best move found, or the zero Move if the position has none.
+	Move chess.Move
+	// Score is the value of the position for the side to move with best play.
+	Score int
+	// Depth is the deepest search that finished.
+	Depth int
+	// Nodes is the number of positions searched.
+	Nodes uint64
+	// PV is the line of best play the search expects, starting with Move.
+	PV []chess.Move
+}
+
+// IsMateScore reports whether score is a forced mate for either side.
+func IsMateScore(score int) bool {
+	return score > Mate-MaxPly || score < -Mate+MaxPly
+}
+
+// Engine searches positions, keeping a transposition table between searches
+// so each move of a game starts from what the searches before it found.
+type Engine struct {
+	table *TranspositionTable
+}
+
+// New returns an engine with a transposition table of hashSize megabytes.
+func New(hashSize int) *Engine {
+	return &Engine{table: NewTranspositionTable(hashSize)}
+}
+
+// Table returns the engine's transposition table.
+func (e *Engine) Table() *TranspositionTable {
+	return e.table
+}
+
+// NewGame forgets the positions searched so far.
+func (e *Engine) NewGame() {
+	e.table.Clear()
+}
+
+// BestMove searches position for the best move for the side to move. The
+// position is left as it was.
+func (e *Engine) BestMove(position *chess.Position, limits Limits) Result {
+	e.table.newSearch()
+	s := newSearcher(position, limits, e.table)
+	return s.run()
+}
+
+// BestMove searches position with a new engine with a table of
+// DefaultHashSize.
+func BestMove(position *chess.Position, limits Limits) Result {
+	return New(DefaultHashSize).BestMove(position, limits)
+}
@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"ryan/chess/pkg/chess"
+)
+
+func TestBestMoveFindsMate(t *testing.T) {
+	for _, want := range []struct {
+		fen   string
+		move  string
+		plies int
+	}{
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "a1a8", 1},
+		{"r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5Q2/PPPP1PPP/RNB1K1NR w KQkq - 0 1", "f3f7", 1},
+		{"r1b1kb1r/pppp1ppp/5q2/4n3/3KP3/2N3PN/PPP4P/R1BQ1B1R b kq - 0 1", "f8c5", 5},
+	} {
+		p, err := chess.ParseFEN(want.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := BestMove(p, Limits{Depth: want.plies})
+		if result.Move.String() != want.move || result.Score != Mate-want.plies {
+			t.Errorf("%s: got %v scoring %d, want %s scoring %d", want.fen, result.Move, result.Score, want.move, Mate-want.plies)
+		}
+		if p.FEN() != want.fen {
+			t.Errorf("%s: search changed the position to %s", want.fen, p.FEN())
+		}
+	}
+}
+
+func TestBestMoveWithoutMoves(t *testing.T) {
+	// Black is stalemated
+	p, err := chess.ParseFEN("k7/2Q5/1K6/8/8/8/8/8 b - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, limits := range []Limits{{Depth: 3}, {MoveTime: time.Second}} {
+		result := BestMove(p, limits)
+		if result.Move != (chess.Move{}) || result.Move.String() != "0000" || result.Score != 0 || result.Depth != 1 {
+			t.Errorf("%+v: got %v scoring %d at depth %d, want 0000 scoring 0 at depth 1",
+				limits, result.Move, result.Score, result.Depth)
+		}
+	}
+}
+
+func TestBestMoveSeesRecaptures(t *testing.T) {
+	// Taking the pawn on a6 loses the queen, but only after the search's
+	// last ply
+	p, err := chess.ParseFEN("6k1/1p6/p7/8/8/8/8/Q3K3 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := BestMove(p, Limits{Depth: 1}); result.Move.String() == "a1a6" || result.Score < 0 {
+		t.Errorf("got %v scoring %d", result.Move, result.Score)
+	}
+}
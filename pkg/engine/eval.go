@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"ryan/chess/pkg/chess"
+)
+
+// pieceValues are the usual material values in centipawns, indexed by piece
+// type. Move ordering uses them to rank captures.
+var pieceValues = [7]int{chess.Pawn: 100, chess.Knight: 320, chess.Bishop: 330, chess.Rook: 500, chess.Queen: 900}
+
+// Term is one part of the evaluation.
+type Term int
+
+const (
+	Material Term = iota
+	PieceSquares
+	PawnStructure
+	KingSafety
+	Mobility
+	BishopPair
+	termCount
+)
+
+func (t Term) String() string {
+	switch t {
+	case Material:
+		return "Material"
+	case PieceSquares:
+		return "Piece squares"
+	case PawnStructure:
+		return "Pawn structure"
+	case KingSafety:
+		return "King safety"
+	case Mobility:
+		return "Mobility"
+	case BishopPair:
+		return "Bishop pair"
+	}
+	return ""
+}
+
+// maxPhase is the game phase with every piece but the pawns and kings still
+// on the board. Knights and bishops count 1, rooks 2 and queens 4.
+const maxPhase = 24
+
+var phaseWeights = [7]int{chess.Knight: 1, chess.Bishop: 1, chess.Rook: 2, chess.Queen: 4}
+
+// Evaluation is the static score of a position broken down by term.
+type Evaluation struct {
+	// Terms holds each side's middlegame and endgame score for each term.
+	Terms [termCount][3]Weight
+	// Phase runs from maxPhase with all pieces on the board to 0 with only
+	// kings and pawns left.
+	Phase int
+}
+
+// taper blends a middlegame and endgame score by the phase of the game.
+func (e *Evaluation) taper(w Weight) int {
+	return (w.MG*e.Phase + w.EG*(maxPhase-e.Phase)) / maxPhase
+}
+
+// Term returns the score of one term from White's point of view.
+func (e *Evaluation) Term(t Term) int {
+	return e.taper(e.Terms[t][chess.White]) - e.taper(e.Terms[t][chess.Black])
+}
+
+// Score returns the whole evaluation from White's point of view.
+func (e *Evaluation) Score() int {
+	score := 0
+	for t := Term(0); t < termCount; t++ {
+		score += e.Term(t)
+	}
+	return score
+}
+
+// String lays the evaluation out as a table of terms, in pawns.
+func (e *Evaluation) String() string {
+	var b strings.Builder
+	pawns := func(cp int) string {
+		return fmt.Sprintf("%+7.2f", float64(cp)/100)
+	}
+	fmt.Fprintf(&b, "%-16s %15s %15s %7s\n", "Term", "White (MG/EG)", "Black (MG/EG)", "Total")
+	for t := Term(0); t < termCount; t++ {
+		white, black := e.Terms[t][chess.White], e.Terms[t][chess.Black]
+		fmt.Fprintf(&b, "%-16s %s/%s %s/%s %s\n", t, pawns(white.MG), pawns(white.EG), pawns(black.MG), pawns(black.EG), pawns(e.Term(t)))
+	}
+	fmt.Fprintf(&b, "Phase %d/%d, total %s for White\n", e.Phase, maxPhase, strings.TrimSpace(pawns(e.Score())))
+	return b.String()
+}
+
+func (e *Evaluation) add(t Term, c chess.Color, w Weight, n int) {
+	e.Terms[t][c].MG += w.MG * n
+	e.Terms[t][c].EG += w.EG * n
+}
+
+// Evaluate scores the position with DefaultWeights.
+func Evaluate(position *chess.Position) Evaluation {
+	return DefaultWeights.Evaluate(position)
+}
+
+// Evaluate scores the position without searching: material, where the pieces
+// stand, pawn structure, king safety, mobility and the bishop pair.
+func (w *Weights) Evaluate(p *chess.Position) Evaluation {
+	var e Evaluation
+	occupied := p.Occupancy(chess.NoColor)
+
+	for t := chess.Pawn; t <= chess.King; t++ {
+		count := p.Pieces(chess.White, t).Count() + p.Pieces(chess.Black, t).Count()
+		e.Phase += count * phaseWeights[t]
+	}
+	if e.Phase > maxPhase {
+		e.Phase = maxPhase
+	}
+
+	for _, c := range [2]chess.Color{chess.White, chess.Black} {
+		them := c.Other()
+		own := p.Occupancy(c)
+		king := p.Pieces(c, chess.King).First()
+		enemyKingZone := chess.KingAttacks(p.Pieces(them, chess.King).First()) | p.Pieces(them, chess.King)
+		var enemyPawnAttacks chess.Bitboard
+		for pawns := p.Pieces(them, chess.Pawn); pawns != 0; pawns &= pawns - 1 {
+			enemyPawnAttacks |= chess.PawnAttacks(them, pawns.First())
+		}
+
+		for t := chess.Pawn; t <= chess.King; t++ {
+			pieces := p.Pieces(c, t)
+			e.add(Material, c, w.Material.Of(t), pieces.Count())
+
+			table := w.PieceSquare.Of(t)
+			for ; pieces != 0; pieces &= pieces - 1 {
+				sq := pieces.First()
+				index := int(sq)
+				if c == chess.White {
+					index ^= 56
+				}
+				e.add(PieceSquares, c, Weight{table.MG[index], table.EG[index]}, 1)
+
+				// Pieces score for the squares they reach and the squares
+				// they attack around the enemy king, which counts against it
+				var attacks chess.Bitboard
+				switch t {
+				case chess.Knight:
+					attacks = chess.KnightAttacks(sq)
+				case chess.Bishop:
+					attacks = chess.BishopAttacks(sq, occupied)
+				case chess.Rook:
+					attacks = chess.RookAttacks(sq, occupied)
+				case chess.Queen:
+					attacks = chess.QueenAttacks(sq, occupied)
+				default:
+					continue
+				}
+				e.add(Mobility, c, w.Mobility.Of(t), (attacks &^ own &^ enemyPawnAttacks).Count())
+				e.add(KingSafety, them, w.KingAttack, (attacks & enemyKingZone).Count())
+			}
+		}
+
+		if p.Pieces(c, chess.Bishop).Count() >= 2 {
+			e.add(BishopPair, c, w.BishopPair, 1)
+		}
+
+		w.evaluatePawns(p, c, &e)
+
+		// The shield is the pawns on the king's and neighbouring files, one
+		// and two ranks towards the enemy
+		beside := (adjacentFiles(king.File()) | chess.FileMask(king.File())) & chess.RankMask(king.Rank())
+		shield := beside<<8 | beside<<16
+		if c == chess.Black {
+			shield = beside>>8 | beside>>16
+		}
+		e.add(KingSafety, c, w.KingShield, (p.Pieces(c, chess.Pawn) & shield).Count())
+	}
+	return e
+}
+
+// evaluatePawns scores the pawn structure of color c.
+func (w *Weights) evaluatePawns(p *chess.Position, c chess.Color, e *Evaluation) {
+	pawns, enemyPawns := p.Pieces(c, chess.Pawn), p.Pieces(c.Other(), chess.Pawn)
+	for file := 0; file < 8; file++ {
+		count := (pawns & chess.FileMask(file)).Count()
+		if count > 1 {
+			e.add(PawnStructure, c, w.DoubledPawn, count-1)
+		}
+		if count > 0 && pawns&adjacentFiles(file) == 0 {
+			e.add(PawnStructure, c, w.IsolatedPawn, count)
+		}
+	}
+
+	for b := pawns; b != 0; b &= b - 1 {
+		sq := b.First()
+		rank, ahead := sq.Rank(), chess.Bitboard(0)
+		if c == chess.White {
+			ahead = ^chess.Bitboard(0) << (8 * (rank + 1))
+		} else {
+			ahead = chess.Bitboard(1)<<(8*rank) - 1
+			rank = 7 - rank
+		}
+		if enemyPawns&ahead&(adjacentFiles(sq.File())|chess.FileMask(sq.File())) == 0 {
+			e.add(PawnStructure, c, w.PassedPawn[rank], 1)
+		}
+	}
+}
+
+// adjacentFiles returns the files either side of file.
+func adjacentFiles(file int) chess.Bitboard {
+	var files chess.Bitboard
+	if file > 0 {
+		files |= chess.FileMask(file - 1)
+	}
+	if file < 7 {
+		files |= chess.FileMask(file + 1)
+	}
+	return files
+}
+
+// evaluate scores the position for the side to move.
+func (s *searcher) evaluate() int {
+	e := s.weights.Evaluate(s.position)
+	if s.position.Turn() == chess.Black {
+		return -e.Score()
+	}
+	return e.Score()
+}
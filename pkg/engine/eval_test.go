@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"ryan/chess/pkg/chess"
+)
+
+// mirrorFEN swaps the colors of a position, turning the board upside down so
+// each side stands where the other did.
+func mirrorFEN(fen string) string {
+	fields := strings.Fields(fen)
+	ranks := strings.Split(fields[0], "/")
+	for i, j := 0, len(ranks)-1; i < j; i, j = i+1, j-1 {
+		ranks[i], ranks[j] = ranks[j], ranks[i]
+	}
+	swapCase := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r - 'a' + 'A'
+			}
+			if r >= 'A' && r <= 'Z' {
+				return r - 'A' + 'a'
+			}
+			return r
+		}, s)
+	}
+	fields[0] = swapCase(strings.Join(ranks, "/"))
+	fields[1] = map[string]string{"w": "b", "b": "w"}[fields[1]]
+	if fields[2] != "-" {
+		fields[2] = swapCase(fields[2])
+	}
+	if fields[3] != "-" {
+		fields[3] = fields[3][:1] + map[byte]string{'3': "6", '6': "3"}[fields[3][1]]
+	}
+	return strings.Join(fields, " ")
+}
+
+func TestEvaluateIsSymmetric(t *testing.T) {
+	for _, fen := range []string{
+		chess.StartingFEN,
+		"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		"r1b1kb1r/pppp1ppp/5q2/4n3/3KP3/2N3PN/PPP4P/R1BQ1B1R b kq - 0 1",
+		"8/5k2/8/3P4/8/8/6K1/8 w - - 0 1",
+	} {
+		p, err := chess.ParseFEN(fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mirrored, err := chess.ParseFEN(mirrorFEN(fen))
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, m := Evaluate(p), Evaluate(mirrored)
+		for term := Term(0); term < termCount; term++ {
+			if e.Term(term) != -m.Term(term) {
+				t.Errorf("%s: %v scores %d, but %d with the colors swapped", fen, term, e.Term(term), m.Term(term))
+			}
+		}
+	}
+
+	if e := Evaluate(chess.StartingPosition()); e.Score() != 0 || e.Phase != maxPhase {
+		t.Errorf("starting position scores %d in phase %d, want 0 in phase %d", e.Score(), e.Phase, maxPhase)
+	}
+}
+
+func TestEvaluateTerms(t *testing.T) {
+	// White has a passed pawn on d5, doubled isolated pawns on the h-file and
+	// both bishops; Black has only its king's pawns
+	p, err := chess.ParseFEN("6k1/5ppp/8/3P4/8/7P/3BB2P/6K1 w - - 0 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Evaluate(p)
+	w := &DefaultWeights
+	white := e.Terms[PawnStructure][chess.White]
+	want := Weight{
+		w.PassedPawn[4].MG + w.DoubledPawn.MG + 3*w.IsolatedPawn.MG,
+		w.PassedPawn[4].EG + w.DoubledPawn.EG + 3*w.IsolatedPawn.EG,
+	}
+	if white != want {
+		t.Errorf("White pawn structure is %+v, want %+v", white, want)
+	}
+	if e.Terms[BishopPair][chess.White] != w.BishopPair || e.Terms[BishopPair][chess.Black] != (Weight{}) {
+		t.Errorf("bishop pair is %+v for White and %+v for Black", e.Terms[BishopPair][chess.White], e.Terms[BishopPair][chess.Black])
+	}
+	if e.Phase != 2 {
+		t.Errorf("phase is %d, want 2", e.Phase)
+	}
+}
+
+func TestReadWeights(t *testing.T) {
+	w, err := ReadWeights(strings.NewReader(`{"bishop_pair": {"mg": 1, "eg": 2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.BishopPair != (Weight{1, 2}) || w.Material != DefaultWeights.Material {
+		t.Errorf("got bishop pair %+v and material %+v", w.BishopPair, w.Material)
+	}
+	if _, err := ReadWeights(strings.NewReader(`{"bishop_pairs": {}}`)); err == nil {
+		t.Error("unknown weight was accepted")
+	}
+}
@@ -0,0 +1,287 @@
+package engine
+
+import (
+	"time"
+
+	"ryan/chess/pkg/chess"
+)
+
+// searcher holds the state of one search: its own copy of the position, the
+// limits, and per-ply move lists, principal variation and killer moves.
+type searcher struct {
+	position *chess.Position
+	limits   Limits
+	weights  *Weights
+	table    *TranspositionTable
+	deadline time.Time
+	canStop  bool
+	stopped  bool
+	nodes    uint64
+
+	moves   [MaxPly][]chess.Move
+	scores  [MaxPly][]int
+	killers [MaxPly][2]chess.Move
+
+	// pv[ply][ply:pvLength[ply]] is the best line found from ply on
+	pv       [MaxPly + 1][MaxPly + 1]chess.Move
+	pvLength [MaxPly + 1]int
+	lastPV   []chess.Move
+}
+
+func newSearcher(position *chess.Position, limits Limits, table *TranspositionTable) *searcher {
+	s := &searcher{position: position.Clone(), limits: limits, weights: &DefaultWeights, table: table}
+	if limits.MoveTime > 0 {
+		s.deadline = time.Now().Add(limits.MoveTime)
+	}
+	return s
+}
+
+// run deepens the search one ply at a time until a limit is reached. Each
+// depth starts from the previous one's best line, which makes the deeper
+// search faster than it would be on its own.
+func (s *searcher) run() Result {
+	maxDepth := s.limits.Depth
+	switch {
+	case maxDepth <= 0 && s.limits.MoveTime <= 0:
+		maxDepth = DefaultDepth
+	case maxDepth <= 0 || maxDepth >= MaxPly:
+		maxDepth = MaxPly - 1
+	}
+
+	var result Result
+	for depth := 1; depth <= maxDepth; depth++ {
+		score := s.negamax(depth, 0, -infinity, infinity)
+		if s.stopped {
+			break
+		}
+		s.lastPV = append([]chess.Move(nil), s.pv[0][:s.pvLength[0]]...)
+		result = Result{Score: score, Depth: depth, PV: s.lastPV}
+		if len(s.lastPV) > 0 {
+			result.Move = s.lastPV[0]
+		}
+
+		// The first depth always finishes so there is a move to play
+		s.canStop = true
+		if len(s.moves[0]) == 0 {
+			// Checkmate or stalemate, which deeper searches won't change
+			break
+		}
+		if IsMateScore(score) && Mate-abs(score) <= depth {
+			// A forced mate that deeper searches can't improve on
+			break
+		}
+	}
+	result.Nodes = s.nodes
+	return result
+}
+
+// negamax returns the score of the position for the side to move, searched
+// depth plies deep. Scores at or below alpha only show the move leading here
+// is no better than one already found, and scores at or above beta that the
+// opponent will avoid this position; both end the search early.
+func (s *searcher) negamax(depth, ply, alpha, beta int) int {
+	p := s.position
+	if depth <= 0 && !p.InCheck() {
+		return s.quiesce(ply, alpha, beta)
+	}
+	if s.visit(ply) {
+		return 0
+	}
+	if ply > 0 && (p.HalfmoveClock() >= 100 || p.IsRepetition()) {
+		return 0
+	}
+
+	// Checks are searched a ply deeper so forcing lines aren't cut short
+	inCheck := p.InCheck()
+	if inCheck {
+		depth++
+	}
+	if ply >= MaxPly-1 {
+		return s.evaluate()
+	}
+
+	// A search of this position reached by other moves may settle it. The
+	// root is always searched so there is a move to play.
+	hash := p.Hash()
+	entry, found := s.table.probe(hash, ply)
+	if found && ply > 0 && entry.depth >= depth {
+		switch {
+		case entry.bound == exactBound,
+			entry.bound == lowerBound && entry.score >= beta,
+			entry.bound == upperBound && entry.score <= alpha:
+			return entry.score
+		}
+	}
+
+	moves := p.AppendLegalMoves(s.moves[ply][:0])
+	s.moves[ply] = moves
+	if len(moves) == 0 {
+		if inCheck {
+			return -Mate + ply
+		}
+		return 0
+	}
+	s.orderMoves(moves, ply, entry.move)
+
+	best, bestMove, originalAlpha := -infinity, chess.Move{}, alpha
+	for _, m := range moves {
+		p.MakeMove(m)
+		score := -s.negamax(depth-1, ply+1, -beta, -alpha)
+		p.UnmakeMove()
+		if s.stopped {
+			return 0
+		}
+
+		if score > best {
+			best = score
+		}
+		if score > alpha {
+			alpha = score
+			bestMove = m
+			s.pv[ply][ply] = m
+			copy(s.pv[ply][ply+1:], s.pv[ply+1][ply+1:s.pvLength[ply+1]])
+			s.pvLength[ply] = s.pvLength[ply+1]
+		}
+		if alpha >= beta {
+			if !m.IsCapture() && !m.IsPromotion() && m != s.killers[ply][0] {
+				s.killers[ply][1] = s.killers[ply][0]
+				s.killers[ply][0] = m
+			}
+			break
+		}
+	}
+
+	stored := ttEntry{move: bestMove, score: best, depth: depth, bound: exactBound}
+	switch {
+	case best >= beta:
+		stored.bound = lowerBound
+	case best <= originalAlpha:
+		stored.bound = upperBound
+	}
+	s.table.store(hash, ply, stored)
+	return best
+}
+
+// quiesce searches on from the end of the main search until the position is
+// quiet, so it is never scored in the middle of an exchange. The side to move
+// may stand on the position's score or try captures and queen promotions that
+// don't lose material; in check it must try every evasion.
+func (s *searcher) quiesce(ply, alpha, beta int) int {
+	if s.visit(ply) {
+		return 0
+	}
+	p := s.position
+	if p.HalfmoveClock() >= 100 || p.IsRepetition() {
+		return 0
+	}
+	if ply >= MaxPly-1 {
+		return s.evaluate()
+	}
+
+	inCheck := p.InCheck()
+	best := -infinity
+	if !inCheck {
+		best = s.evaluate()
+		if best >= beta {
+			return best
+		}
+		if best > alpha {
+			alpha = best
+		}
+	}
+
+	moves := p.AppendLegalMoves(s.moves[ply][:0])
+	s.moves[ply] = moves
+	if len(moves) == 0 && inCheck {
+		return -Mate + ply
+	}
+	if !inCheck {
+		n := 0
+		for _, m := range moves {
+			switch {
+			case !m.IsCapture() && !m.IsPromotion():
+			case m.IsPromotion() && m.Promotion != chess.Queen:
+				// Underpromotions are left to the main search
+			case p.SEE(m) < 0:
+			default:
+				moves[n] = m
+				n++
+			}
+		}
+		moves = moves[:n]
+	}
+	s.orderMoves(moves, ply, chess.Move{})
+
+	for _, m := range moves {
+		p.MakeMove(m)
+		score := -s.quiesce(ply+1, -beta, -alpha)
+		p.UnmakeMove()
+		if s.stopped {
+			return 0
+		}
+
+		if score > best {
+			best = score
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+// visit counts a node and reports whether the search has run out of time.
+func (s *searcher) visit(ply int) bool {
+	s.pvLength[ply] = ply
+	if s.nodes&2047 == 0 && s.canStop && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		s.stopped = true
+	}
+	if s.stopped {
+		return true
+	}
+	s.nodes++
+	return false
+}
+
+// orderMoves sorts moves so the ones most likely to be best are searched
+// first: the transposition table's move, the previous depth's best line, then
+// captures of valuable pieces by cheap ones, promotions, and quiet moves that
+// caused cutoffs at this ply.
+func (s *searcher) orderMoves(moves []chess.Move, ply int, hashMove chess.Move) {
+	scores := s.scores[ply][:0]
+	for _, m := range moves {
+		score := 0
+		switch {
+		case sameMove(m, hashMove):
+			score = 1 << 21
+		case ply < len(s.lastPV) && m == s.lastPV[ply]:
+			score = 1 << 20
+		case m.IsCapture() || m.IsPromotion():
+			score = 1<<16 + 16*pieceValues[m.Captured.Type] - pieceValues[m.Piece.Type] + pieceValues[m.Promotion]
+		case m == s.killers[ply][0]:
+			score = 1<<15 + 1
+		case m == s.killers[ply][1]:
+			score = 1 << 15
+		}
+		scores = append(scores, score)
+	}
+	s.scores[ply] = scores
+
+	// Insertion sort, highest score first; the lists are short
+	for i := 1; i < len(moves); i++ {
+		for j := i; j > 0 && scores[j] > scores[j-1]; j-- {
+			moves[j], moves[j-1] = moves[j-1], moves[j]
+			scores[j], scores[j-1] = scores[j-1], scores[j]
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,235 @@
+package engine
+
+import (
+	"sync/atomic"
+
+	"ryan/chess/pkg/chess"
+)
+
+// DefaultHashSize is the size in megabytes of the transposition table of an
+// Engine made without one.
+const DefaultHashSize = 16
+
+// bound tells how a stored score relates to the true score of the position.
+type bound uint8
+
+const (
+	noBound bound = iota
+	// exactBound is a score every move was searched for.
+	exactBound
+	// lowerBound is a score from a move good enough to cut the search off;
+	// the position is worth at least this much.
+	lowerBound
+	// upperBound is a score no move beat; the position is worth at most this
+	// much.
+	upperBound
+)
+
+// ttEntry is what the table remembers of a searched position.
+type ttEntry struct {
+	// move is the best move found. Only From, To and Promotion are kept.
+	move  chess.Move
+	score int
+	depth int
+	bound bound
+	age   uint8
+}
+
+// A slot holds an entry packed into data, and the position's hash XORed with
+// data. A slot torn by two searches writing it at once then fails to match
+// either hash, so the table needs no locks.
+type ttSlot struct {
+	key  uint64
+	data uint64
+}
+
+// TranspositionTable remembers positions already searched, by hash, so the
+// search can skip positions reached again by a different order of moves and
+// try the best move found before first.
+//
+// It has a fixed size. When two positions share a slot the entry from the
+// current search, or from the deeper search, is kept.
+type TranspositionTable struct {
+	slots []ttSlot
+	mask  uint64
+	age   uint8
+
+	probes atomic.Uint64
+	hits   atomic.Uint64
+	stores atomic.Uint64
+}
+
+// TableStats counts how a transposition table has been used since the
+// current search began.
+type TableStats struct {
+	// Probes is the number of positions looked up, and Hits the number found.
+	Probes, Hits uint64
+	// Stores is the number of entries written.
+	Stores uint64
+	// Full is the fraction of the table holding entries from this search.
+	Full float64
+}
+
+// HitRate returns the fraction of probes that found their position.
+func (s TableStats) HitRate() float64 {
+	if s.Probes == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Probes)
+}
+
+// NewTranspositionTable returns an empty table of at most megabytes in size,
+// and at least one megabyte.
+func NewTranspositionTable(megabytes int) *TranspositionTable {
+	if megabytes < 1 {
+		megabytes = 1
+	}
+	// A power of two slots so the hash can be masked down to an index
+	n := uint64(1)
+	for n*2*16 <= uint64(megabytes)<<20 {
+		n *= 2
+	}
+	return &TranspositionTable{slots: make([]ttSlot, n), mask: n - 1}
+}
+
+// Size returns the size of the table in bytes.
+func (t *TranspositionTable) Size() int {
+	return len(t.slots) * 16
+}
+
+// Clear empties the table, as at the start of a new game.
+func (t *TranspositionTable) Clear() {
+	for i := range t.slots {
+		atomic.StoreUint64(&t.slots[i].key, 0)
+		atomic.StoreUint64(&t.slots[i].data, 0)
+	}
+	t.age = 0
+	t.resetStats()
+}
+
+// Stats returns the table's statistics for the current search.
+func (t *TranspositionTable) Stats() TableStats {
+	stats := TableStats{Probes: t.probes.Load(), Hits: t.hits.Load(), Stores: t.stores.Load()}
+
+	// Sample the first thousand slots rather than count them all
+	sample, used := len(t.slots), 0
+	if sample > 1000 {
+		sample = 1000
+	}
+	for i := 0; i < sample; i++ {
+		data := atomic.LoadUint64(&t.slots[i].data)
+		if e := unpack(data); e.bound != noBound && e.age == t.age {
+			used++
+		}
+	}
+	stats.Full = float64(used) / float64(sample)
+	return stats
+}
+
+func (t *TranspositionTable) resetStats() {
+	t.probes.Store(0)
+	t.hits.Store(0)
+	t.stores.Store(0)
+}
+
+// newSearch ages the entries already in the table, so the current search's
+// replace them first, and starts the statistics over.
+func (t *TranspositionTable) newSearch() {
+	t.age++
+	t.resetStats()
+}
+
+// probe looks up the position with hash, reached ply plies into the search.
+func (t *TranspositionTable) probe(hash uint64, ply int) (ttEntry, bool) {
+	t.probes.Add(1)
+	slot := &t.slots[hash&t.mask]
+	key, data := atomic.LoadUint64(&slot.key), atomic.LoadUint64(&slot.data)
+	e := unpack(data)
+	if key^data != hash || e.bound == noBound {
+		return ttEntry{}, false
+	}
+	t.hits.Add(1)
+	e.score = scoreFromTable(e.score, ply)
+	return e, true
+}
+
+// store records the search of the position with hash, reached ply plies into
+// the search, unless its slot holds a more useful entry.
+func (t *TranspositionTable) store(hash uint64, ply int, e ttEntry) {
+	slot := &t.slots[hash&t.mask]
+	key, data := atomic.LoadUint64(&slot.key), atomic.LoadUint64(&slot.data)
+	old := unpack(data)
+	if key^data != hash && old.bound != noBound && old.age == t.age && old.depth > e.depth {
+		return
+	}
+	if key^data == hash && e.move == (chess.Move{}) {
+		// Keep the best move of an earlier search of this position
+		e.move = old.move
+	}
+
+	e.score = scoreToTable(e.score, ply)
+	e.age = t.age
+	data = pack(e)
+	atomic.StoreUint64(&slot.key, hash^data)
+	atomic.StoreUint64(&slot.data, data)
+	t.stores.Add(1)
+}
+
+// Mate scores count plies from the root of the search, but a position can be
+// reached at any ply, so the table counts them from the position instead.
+func scoreToTable(score, ply int) int {
+	switch {
+	case score > Mate-MaxPly:
+		return score + ply
+	case score < -Mate+MaxPly:
+		return score - ply
+	}
+	return score
+}
+
+func scoreFromTable(score, ply int) int {
+	switch {
+	case score > Mate-MaxPly:
+		return score - ply
+	case score < -Mate+MaxPly:
+		return score + ply
+	}
+	return score
+}
+
+// An entry packs into 64 bits as the move's From, To and Promotion in bits 0
+// to 14, the score in bits 16 to 31, the depth in 32 to 39, the bound in 40
+// and 41 and the age in 48 to 55.
+func pack(e ttEntry) uint64 {
+	var move uint64
+	if e.move != (chess.Move{}) {
+		move = uint64(e.move.From) | uint64(e.move.To)<<6 | uint64(e.move.Promotion)<<12
+	}
+	return move |
+		uint64(uint16(int16(e.score)))<<16 |
+		uint64(uint8(e.depth))<<32 |
+		uint64(e.bound)<<40 |
+		uint64(e.age)<<48
+}
+
+func unpack(data uint64) ttEntry {
+	e := ttEntry{
+		score: int(int16(uint16(data >> 16))),
+		depth: int(uint8(data >> 32)),
+		bound: bound(data>>40) & 3,
+		age:   uint8(data >> 48),
+	}
+	if move := data & 0x7fff; move != 0 {
+		e.move = chess.Move{
+			From:      chess.Square(move & 63),
+			To:        chess.Square(move >> 6 & 63),
+			Promotion: chess.PieceType(move >> 12),
+		}
+	}
+	return e
+}
+
+// sameMove reports whether m is the move the table stored as stored.
+func sameMove(m, stored chess.Move) bool {
+	return m.From == stored.From && m.To == stored.To && m.Promotion == stored.Promotion
+}
@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"testing"
+
+	"ryan/chess/pkg/chess"
+)
+
+func TestTranspositionTableStore(t *testing.T) {
+	table := NewTranspositionTable(1)
+	if table.Size() != 1<<20 {
+		t.Errorf("1MB table is %d bytes", table.Size())
+	}
+
+	move := chess.Move{From: chess.E7, To: chess.E8, Promotion: chess.Queen}
+	table.store(0x1234, 3, ttEntry{move: move, score: -250, depth: 7, bound: lowerBound})
+	e, ok := table.probe(0x1234, 5)
+	if !ok || e.move != move || e.score != -250 || e.depth != 7 || e.bound != lowerBound {
+		t.Errorf("got %+v, %v", e, ok)
+	}
+	if _, ok := table.probe(0x1234+uint64(len(table.slots)), 3); ok {
+		t.Error("found a different position sharing the slot")
+	}
+
+	// A mate found 4 plies into the search, 2 plies past the position
+	table.store(0x5678, 2, ttEntry{score: Mate - 4, depth: 1, bound: exactBound})
+	if e, _ := table.probe(0x5678, 6); e.score != Mate-8 {
+		t.Errorf("mate reached at ply 6 scores %d, want %d", e.score, Mate-8)
+	}
+	table.store(0x5678, 2, ttEntry{score: -Mate + 4, depth: 1, bound: exactBound})
+	if e, _ := table.probe(0x5678, 0); e.score != -Mate+2 {
+		t.Errorf("mated reached at ply 0 scores %d, want %d", e.score, -Mate+2)
+	}
+
+	stats := table.Stats()
+	if stats.Probes != 4 || stats.Hits != 3 || stats.Stores != 3 || stats.HitRate() != 0.75 {
+		t.Errorf("got stats %+v", stats)
+	}
+	table.Clear()
+	if _, ok := table.probe(0x1234, 0); ok {
+		t.Error("found a position after clearing")
+	}
+}
+
+func TestTranspositionTableReplacement(t *testing.T) {
+	table := NewTranspositionTable(1)
+	other := 0x1234 + uint64(len(table.slots))
+
+	table.store(0x1234, 0, ttEntry{depth: 8, bound: exactBound})
+	table.store(other, 0, ttEntry{depth: 3, bound: exactBound})
+	if _, ok := table.probe(0x1234, 0); !ok {
+		t.Error("shallower entry replaced a deeper one from the same search")
+	}
+
+	table.newSearch()
+	table.store(other, 0, ttEntry{depth: 3, bound: exactBound})
+	if _, ok := table.probe(other, 0); !ok {
+		t.Error("entry from an earlier search was kept")
+	}
+}
+
+func TestEngineReusesTable(t *testing.T) {
+	e := New(1)
+	p := chess.StartingPosition()
+	first := e.BestMove(p, Limits{Depth: 4})
+	second := e.BestMove(p, Limits{Depth: 4})
+	if second.Move != first.Move || second.Score != first.Score {
+		t.Errorf("second search found %v scoring %d, first %v scoring %d", second.Move, second.Score, first.Move, first.Score)
+	}
+	if second.Nodes >= first.Nodes {
+		t.Errorf("second search took %d nodes, first %d", second.Nodes, first.Nodes)
+	}
+	if stats := e.Table().Stats(); stats.Hits == 0 {
+		t.Errorf("got stats %+v", stats)
+	}
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"ryan/chess/pkg/chess"
+)
+
+// Weight is a value in centipawns for the middlegame and for the endgame.
+// Evaluate blends the two by how much material is left on the board.
+type Weight struct {
+	MG int `json:"mg"`
+	EG int `json:"eg"`
+}
+
+// PieceWeights holds a Weight for each type of piece.
+type PieceWeights struct {
+	Pawn   Weight `json:"pawn"`
+	Knight Weight `json:"knight"`
+	Bishop Weight `json:"bishop"`
+	Rook   Weight `json:"rook"`
+	Queen  Weight `json:"queen"`
+	King   Weight `json:"king"`
+}
+
+// Of returns the weight for pieces of type t.
+func (pw *PieceWeights) Of(t chess.PieceType) Weight {
+	switch t {
+	case chess.Pawn:
+		return pw.Pawn
+	case chess.Knight:
+		return pw.Knight
+	case chess.Bishop:
+		return pw.Bishop
+	case chess.Rook:
+		return pw.Rook
+	case chess.Queen:
+		return pw.Queen
+	case chess.King:
+		return pw.King
+	}
+	return Weight{}
+}
+
+// PieceSquareTable is the bonus for a piece standing on each square, listed
+// from a8 to h1 as the board looks from White's side. Black's pieces use the
+// table mirrored top to bottom.
+type PieceSquareTable struct {
+	MG [64]int `json:"mg"`
+	EG [64]int `json:"eg"`
+}
+
+// PieceSquareTables holds a PieceSquareTable for each type of piece.
+type PieceSquareTables struct {
+	Pawn   PieceSquareTable `json:"pawn"`
+	Knight PieceSquareTable `json:"knight"`
+	Bishop PieceSquareTable `json:"bishop"`
+	Rook   PieceSquareTable `json:"rook"`
+	Queen  PieceSquareTable `json:"queen"`
+	King   PieceSquareTable `json:"king"`
+}
+
+// Of returns the table for pieces of type t.
+func (pst *PieceSquareTables) Of(t chess.PieceType) *PieceSquareTable {
+	switch t {
+	case chess.Pawn:
+		return &pst.Pawn
+	case chess.Knight:
+		return &pst.Knight
+	case chess.Bishop:
+		return &pst.Bishop
+	case chess.Rook:
+		return &pst.Rook
+	case chess.Queen:
+		return &pst.Queen
+	}
+	return &pst.King
+}
+
+// Weights are the numbers the evaluation is built from. They are read from
+// JSON data files so they can be tuned without changing the code.
+type Weights struct {
+	Material    PieceWeights      `json:"material"`
+	PieceSquare PieceSquareTables `json:"piece_square"`
+
+	// Pawn structure: each pawn beyond the first on a file, each pawn with
+	// no friendly pawn on a neighbouring file, and each pawn no enemy pawn
+	// can stop, by its rank counted from its own side
+	DoubledPawn  Weight    `json:"doubled_pawn"`
+	IsolatedPawn Weight    `json:"isolated_pawn"`
+	PassedPawn   [8]Weight `json:"passed_pawn"`
+
+	// King safety: each friendly pawn on the two ranks in front of the king,
+	// and each attack by an enemy piece on a square around it
+	KingShield Weight `json:"king_shield"`
+	KingAttack Weight `json:"king_attack"`
+
+	// Mobility is per square a piece can move to that isn't guarded by an
+	// enemy pawn
+	Mobility   PieceWeights `json:"mobility"`
+	BishopPair Weight       `json:"bishop_pair"`
+}
+
+//go:embed weights.json
+var defaultWeightsJSON []byte
+
+// DefaultWeights are the weights Evaluate and BestMove use.
+var DefaultWeights Weights
+
+func init() {
+	if err := json.Unmarshal(defaultWeightsJSON, &DefaultWeights); err != nil {
+		panic(fmt.Errorf("engine: default weights: %w", err))
+	}
+}
+
+// ReadWeights reads weights in JSON, in the layout of the package's
+// weights.json. Weights missing from the data keep their default values.
+func ReadWeights(r io.Reader) (Weights, error) {
+	w := DefaultWeights
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&w); err != nil {
+		return Weights{}, fmt.Errorf("engine: invalid weights: %w", err)
+	}
+	return w, nil
+}
+
+// LoadWeights reads weights from the JSON file at path.
+func LoadWeights(path string) (Weights, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Weights{}, err
+	}
+	defer file.Close()
+	return ReadWeights(file)
+}